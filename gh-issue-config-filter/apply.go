@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+)
+
+// rollbackTimeout bounds the rollback of a failed issue, which outlives a cancelled run.
+const rollbackTimeout = time.Minute
+
+// ApplyStep is one of the steps taken to create an issue and register it to its project.
+type ApplyStep string
+
+const (
+	StepCreateIssue      ApplyStep = "create_issue"
+	StepResolveNodeID    ApplyStep = "resolve_node_id"
+	StepAddProjectItem   ApplyStep = "add_project_item"
+	StepUpdateFieldValue ApplyStep = "update_field_value"
+)
+
+// ApplyError reports the step at which applying an issue failed.
+type ApplyError struct {
+	Step  ApplyStep
+	Title string
+	// FieldID is set for StepUpdateFieldValue
+	FieldID string
+	Err     error
+}
+
+func (e *ApplyError) Error() string {
+	if e.FieldID != "" {
+		return fmt.Sprintf("%s failed for issue '%s' (field %s): %v", e.Step, e.Title, e.FieldID, e.Err)
+	}
+	return fmt.Sprintf("%s failed for issue '%s': %v", e.Step, e.Title, e.Err)
+}
+
+func (e *ApplyError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyResult is an issue created and registered to its project.
+type ApplyResult struct {
+	Title    string `json:"title"`
+	IssueURL string `json:"issue_url"`
+	ItemID   string `json:"item_id"`
+}
+
+// LoadIssueOutputs reads the JSON printed by the filter.
+func LoadIssueOutputs(r io.Reader) ([]IssueOutput, error) {
+	var outputs []IssueOutput
+	if err := json.NewDecoder(r).Decode(&outputs); err != nil {
+		return nil, fmt.Errorf("failed to parse issues: %w", err)
+	}
+	return outputs, nil
+}
+
+// ApplyOptions configures ApplyIssues.
+type ApplyOptions struct {
+	// Interval is the time to wait between issues, shared by all workers
+	Interval time.Duration
+	// Concurrency is the number of issues applied at a time; below 1 means one
+	Concurrency int
+	// OnFailure is the policy for an occurrence that fails part-way: rollback, continue or resume
+	OnFailure string
+	// RollbackIssues is how rollback undoes a created issue: close or delete
+	RollbackIssues string
+	// Log records the steps taken; nil keeps them in memory only
+	Log *TransactionLog
+}
+
+// ValidateOnFailurePolicy checks the value of --on-failure.
+func ValidateOnFailurePolicy(policy string) error {
+	switch policy {
+	case OnFailureRollback, OnFailureContinue, OnFailureResume:
+		return nil
+	default:
+		return fmt.Errorf("invalid value '%s' (must be 'rollback', 'continue' or 'resume')", policy)
+	}
+}
+
+// ApplyIssues creates every issue that does not exist yet, opts.Concurrency at a time and starting
+// one every opts.Interval. Results are in the order of outputs whatever order issues finish in.
+// Each occurrence is a transaction in opts.Log: completed ones are never applied twice, and one
+// that fails is rolled back, left as is, or resumed on the next run according to opts.OnFailure.
+// The remaining occurrences are applied in any case, and the failures are returned together.
+func ApplyIssues(ctx context.Context, ghClient GitHubClient, outputs []IssueOutput, opts ApplyOptions) ([]ApplyResult, error) {
+	txLog := opts.Log
+	if txLog == nil {
+		txLog, _ = LoadTransactionLog("")
+	}
+
+	var pending []*Transaction
+	if opts.OnFailure == OnFailureResume {
+		for _, tx := range txLog.Unfinished() {
+			log.Printf("Resuming issue from an earlier run: %s", tx.Issue.Title)
+			tx.resumed = true
+			pending = append(pending, tx)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, tx := range pending {
+		seen[tx.Key] = true
+	}
+	for _, output := range outputs {
+		if output.Status == StatusExists {
+			log.Printf("Skipping existing issue: %s (%s)", output.Title, output.ExistingIssueURL)
+			continue
+		}
+		key := transactionKey(output)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if tx := txLog.Get(key); tx != nil {
+			switch tx.Status {
+			case TransactionCompleted:
+				log.Printf("Skipping issue applied in an earlier run: %s (%s)", output.Title, tx.IssueURL)
+				continue
+			case TransactionPending, TransactionFailed:
+				// Left for a later run with --on-failure resume
+				log.Printf("Skipping issue that failed in an earlier run at %s: %s (%s)", tx.FailedStep, output.Title, tx.Error)
+				continue
+			}
+		}
+		pending = append(pending, txLog.Begin(output))
+	}
+
+	pace := newPacer(opts.Interval)
+	errs := runConcurrently(ctx, opts.Concurrency, len(pending), func(ctx context.Context, i int) error {
+		if err := pace.Wait(ctx); err != nil {
+			return err
+		}
+		return applyPending(ctx, ghClient, txLog, pending[i], opts)
+	})
+
+	results := make([]ApplyResult, 0, len(pending))
+	var failures []error
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, err)
+			continue
+		}
+		results = append(results, pending[i].result())
+	}
+
+	if len(failures) > 0 {
+		return results, fmt.Errorf("%d of %d issue(s) failed: %w", len(failures), len(pending), errors.Join(failures...))
+	}
+	return results, nil
+}
+
+// applyPending applies tx and, if it fails, records the failure and handles it according to opts.OnFailure.
+func applyPending(ctx context.Context, ghClient GitHubClient, txLog *TransactionLog, tx *Transaction, opts ApplyOptions) error {
+	err := applyTransaction(ctx, ghClient, txLog, tx)
+	if err == nil {
+		return nil
+	}
+
+	var failedStep ApplyStep
+	var applyErr *ApplyError
+	if errors.As(err, &applyErr) {
+		failedStep = applyErr.Step
+	}
+	status := TransactionFailed
+	if opts.OnFailure == OnFailureRollback {
+		// Roll back even when the run was cancelled, e.g. by SIGTERM, which is often why the issue failed
+		rollbackCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rollbackTimeout)
+		defer cancel()
+		if rollbackErr := rollbackTransaction(rollbackCtx, ghClient, txLog, tx, opts.RollbackIssues); rollbackErr != nil {
+			err = fmt.Errorf("%w (rollback failed: %v)", err, rollbackErr)
+		} else {
+			status = TransactionRolledBack
+		}
+	}
+	if saveErr := txLog.Update(tx, func(tx *Transaction) {
+		tx.Status = status
+		tx.FailedStep = failedStep
+		tx.Error = err.Error()
+	}); saveErr != nil {
+		return errors.Join(err, saveErr)
+	}
+	log.Printf("Failed: %v", err)
+	return err
+}
+
+// applyTransaction takes the steps of tx that have not been taken yet, recording each one in txLog.
+func applyTransaction(ctx context.Context, ghClient GitHubClient, txLog *TransactionLog, tx *Transaction) error {
+	output := tx.Issue
+	if output.TargetRepo == nil || output.ProjectID == nil {
+		return fmt.Errorf("issue '%s' has no target_repo or project_id", output.Title)
+	}
+	repo, err := ParseRepo(*output.TargetRepo)
+	if err != nil {
+		return fmt.Errorf("issue '%s': invalid target_repo: %w", output.Title, err)
+	}
+	projectID := *output.ProjectID
+
+	if tx.resumed && tx.IssueNumber == 0 && output.OccurrenceKey != "" {
+		// The earlier run may have created the issue without learning of it, when the
+		// response was lost; its marker tells. Closed ones were rolled back or are done with.
+		// New occurrences are not looked up, as that would spend the search rate limit on
+		// every issue.
+		existing, found, err := FindIssueByOccurrenceKey(ctx, ghClient, repo, output.OccurrenceKey)
+		if err != nil {
+			return &ApplyError{Step: StepCreateIssue, Title: output.Title, Err: fmt.Errorf("failed to look for the issue of an earlier attempt: %w", err)}
+		}
+		if found && existing.State == "open" {
+			log.Printf("Found issue from an earlier attempt: %s", existing.URL)
+			if err := txLog.Update(tx, func(tx *Transaction) {
+				tx.IssueNumber, tx.IssueURL = existing.Number, existing.URL
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if tx.IssueNumber == 0 {
+		log.Printf("Creating issue: %s", output.Title)
+		created, err := ghClient.CreateIssue(ctx, repo, output.Title, output.Body)
+		if err != nil {
+			return &ApplyError{Step: StepCreateIssue, Title: output.Title, Err: err}
+		}
+		log.Printf("Created: %s", created.URL)
+		if err := txLog.Update(tx, func(tx *Transaction) {
+			tx.IssueNumber, tx.IssueNodeID, tx.IssueURL = created.Number, created.NodeID, created.URL
+		}); err != nil {
+			return err
+		}
+	}
+
+	if tx.IssueNodeID == "" {
+		nodeID, err := ghClient.GetIssueNodeID(ctx, repo, tx.IssueNumber)
+		if err != nil {
+			return &ApplyError{Step: StepResolveNodeID, Title: output.Title, Err: err}
+		}
+		if err := txLog.Update(tx, func(tx *Transaction) { tx.IssueNodeID = nodeID }); err != nil {
+			return err
+		}
+	}
+
+	if tx.ItemID == "" {
+		itemID, err := ghClient.AddProjectItem(ctx, projectID, tx.IssueNodeID)
+		if err != nil {
+			return &ApplyError{Step: StepAddProjectItem, Title: output.Title, Err: err}
+		}
+		log.Printf("Added to project. Item ID: %s", itemID)
+		if err := txLog.Update(tx, func(tx *Transaction) { tx.ItemID = itemID }); err != nil {
+			return err
+		}
+	}
+
+	for _, update := range output.FieldUpdates {
+		if tx.fieldSet(update.FieldID) {
+			continue
+		}
+		if err := ghClient.UpdateProjectItemField(ctx, projectID, tx.ItemID, update); err != nil {
+			return &ApplyError{Step: StepUpdateFieldValue, Title: output.Title, FieldID: update.FieldID, Err: err}
+		}
+		Debugf("Set field %s on item %s", update.FieldID, tx.ItemID)
+		if err := txLog.Update(tx, func(tx *Transaction) { tx.FieldsSet = append(tx.FieldsSet, update.FieldID) }); err != nil {
+			return err
+		}
+	}
+
+	return txLog.Update(tx, func(tx *Transaction) {
+		tx.Status = TransactionCompleted
+		tx.FailedStep = ""
+		tx.Error = ""
+	})
+}
@@ -0,0 +1,567 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeGitHub is a fake GitHub API serving the REST and GraphQL calls used by apply.
+type fakeGitHub struct {
+	mu sync.Mutex
+	// failStep makes the given step fail with a GraphQL or HTTP error
+	failStep ApplyStep
+	// omitNodeID leaves node_id out of the create issue response
+	omitNodeID bool
+	// searchable holds the issues found by issue search
+	searchable []ExistingIssue
+
+	created       []map[string]string
+	addedItems    []map[string]interface{}
+	fieldValues   []map[string]interface{}
+	updatedIssues []map[string]interface{}
+	deletedItems  []map[string]interface{}
+	deletedIssues []map[string]interface{}
+	nodeLookups   int
+	searches      int
+}
+
+func (f *fakeGitHub) newClient(t *testing.T) GitHubClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(f.handle))
+	t.Cleanup(server.Close)
+	return NewGitHubClientWithHTTPClient(&http.Client{Transport: &mockTransport{baseURL: server.URL}})
+}
+
+func (f *fakeGitHub) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/issues":
+		if f.failStep == StepCreateIssue {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			json.NewEncoder(w).Encode(map[string]interface{}{"message": "Validation Failed"})
+			return
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		f.created = append(f.created, body)
+		number := len(f.created)
+		response := map[string]interface{}{
+			"number":   number,
+			"html_url": "https://github.com/owner/repo/issues/" + strconv.Itoa(number),
+		}
+		if !f.omitNodeID {
+			response["node_id"] = "I_created"
+		}
+		json.NewEncoder(w).Encode(response)
+
+	case r.Method == http.MethodGet && r.URL.Path == "/search/issues":
+		f.searches++
+		items := make([]map[string]interface{}, 0, len(f.searchable))
+		for _, issue := range f.searchable {
+			items = append(items, map[string]interface{}{
+				"number":   issue.Number,
+				"title":    issue.Title,
+				"body":     issue.Body,
+				"state":    issue.State,
+				"html_url": issue.URL,
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"total_count": len(items), "items": items})
+
+	case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, "/repos/owner/repo/issues/"):
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		f.updatedIssues = append(f.updatedIssues, body)
+		json.NewEncoder(w).Encode(map[string]interface{}{"number": 1})
+
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/repos/owner/repo/issues/"):
+		f.nodeLookups++
+		if f.failStep == StepResolveNodeID {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]interface{}{"message": "Not Found"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"number": 1, "node_id": "I_resolved"})
+
+	case r.Method == http.MethodPost && r.URL.Path == "/graphql":
+		var request struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		switch {
+		case strings.Contains(request.Query, "addProjectV2ItemById"):
+			if f.failStep == StepAddProjectItem {
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"errors": []interface{}{map[string]interface{}{"type": "FORBIDDEN", "message": "Resource not accessible by integration"}},
+				})
+				return
+			}
+			f.addedItems = append(f.addedItems, request.Variables)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"addProjectV2ItemById": map[string]interface{}{"item": map[string]interface{}{"id": "PVTI_1"}}},
+			})
+		case strings.Contains(request.Query, "updateProjectV2ItemFieldValue"):
+			if f.failStep == StepUpdateFieldValue {
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"errors": []interface{}{map[string]interface{}{"type": "INVALID", "message": "Field not found"}},
+				})
+				return
+			}
+			f.fieldValues = append(f.fieldValues, request.Variables)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"updateProjectV2ItemFieldValue": map[string]interface{}{"projectV2Item": map[string]interface{}{"id": "PVTI_1"}}},
+			})
+		case strings.Contains(request.Query, "deleteProjectV2Item"):
+			f.deletedItems = append(f.deletedItems, request.Variables)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"deleteProjectV2Item": map[string]interface{}{"deletedItemId": request.Variables["itemId"]}},
+			})
+		case strings.Contains(request.Query, "deleteIssue"):
+			f.deletedIssues = append(f.deletedIssues, request.Variables)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"deleteIssue": map[string]interface{}{"clientMutationId": nil}},
+			})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func testIssueOutput(title string) IssueOutput {
+	return IssueOutput{
+		Name:          title,
+		Title:         title,
+		Body:          "body\n\n" + OccurrenceMarker(fmt.Sprintf("%x", title)) + "\n",
+		OccurrenceKey: fmt.Sprintf("%x", title),
+		ProjectID:     stringPtr("PVT_1"),
+		TargetRepo:    stringPtr("owner/repo"),
+		FieldUpdates: []FieldUpdate{
+			{FieldID: "PVTF_text", FieldType: "TEXT", Value: stringPtr("hello")},
+			{FieldID: "PVTF_number", FieldType: "NUMBER", Number: float64Ptr(3.5)},
+			{FieldID: "PVTF_select", FieldType: "SINGLE_SELECT", OptionID: stringPtr("OPT_1")},
+		},
+	}
+}
+
+func TestApplyIssues_Steps(t *testing.T) {
+	fake := &fakeGitHub{}
+	client := fake.newClient(t)
+
+	results, err := ApplyIssues(context.Background(), client, []IssueOutput{testIssueOutput("Wash My Cat")}, ApplyOptions{OnFailure: OnFailureContinue})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %+v", results)
+	}
+	if result := results[0]; result.IssueURL != "https://github.com/owner/repo/issues/1" || result.ItemID != "PVTI_1" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	if len(fake.created) != 1 || fake.created[0]["title"] != "Wash My Cat" || !strings.Contains(fake.created[0]["body"], OccurrenceMarker(fmt.Sprintf("%x", "Wash My Cat"))) {
+		t.Errorf("unexpected created issues: %v", fake.created)
+	}
+	if fake.nodeLookups != 0 {
+		t.Errorf("expected the node ID from the create response to be used, got %d lookups", fake.nodeLookups)
+	}
+	expectedItem := map[string]interface{}{"projectId": "PVT_1", "contentId": "I_created"}
+	if len(fake.addedItems) != 1 || !reflect.DeepEqual(fake.addedItems[0], expectedItem) {
+		t.Errorf("expected item %v, got %v", expectedItem, fake.addedItems)
+	}
+
+	expectedValues := []interface{}{
+		map[string]interface{}{"text": "hello"},
+		map[string]interface{}{"number": 3.5},
+		map[string]interface{}{"singleSelectOptionId": "OPT_1"},
+	}
+	if len(fake.fieldValues) != len(expectedValues) {
+		t.Fatalf("expected %d field updates, got %d", len(expectedValues), len(fake.fieldValues))
+	}
+	for i, expected := range expectedValues {
+		if got := fake.fieldValues[i]["value"]; !reflect.DeepEqual(got, expected) {
+			t.Errorf("field update %d: expected value %v, got %v", i, expected, got)
+		}
+		if fake.fieldValues[i]["itemId"] != "PVTI_1" || fake.fieldValues[i]["projectId"] != "PVT_1" {
+			t.Errorf("field update %d: unexpected variables %v", i, fake.fieldValues[i])
+		}
+	}
+}
+
+func TestApplyIssues_ResolvesNodeID(t *testing.T) {
+	fake := &fakeGitHub{omitNodeID: true}
+	if _, err := ApplyIssues(context.Background(), fake.newClient(t), []IssueOutput{testIssueOutput("Wash My Cat")}, ApplyOptions{OnFailure: OnFailureContinue}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fake.nodeLookups != 1 {
+		t.Errorf("expected 1 node ID lookup, got %d", fake.nodeLookups)
+	}
+	if len(fake.addedItems) != 1 || fake.addedItems[0]["contentId"] != "I_resolved" {
+		t.Errorf("expected the resolved node ID to be added, got %v", fake.addedItems)
+	}
+}
+
+func TestApplyIssues_Errors(t *testing.T) {
+	cases := []struct {
+		name          string
+		fake          *fakeGitHub
+		expectFieldID string
+	}{
+		{name: "create issue", fake: &fakeGitHub{failStep: StepCreateIssue}},
+		{name: "resolve node ID", fake: &fakeGitHub{failStep: StepResolveNodeID, omitNodeID: true}},
+		{name: "add project item", fake: &fakeGitHub{failStep: StepAddProjectItem}},
+		{name: "update field value", fake: &fakeGitHub{failStep: StepUpdateFieldValue}, expectFieldID: "PVTF_text"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			output := testIssueOutput("Wash My Cat")
+			txLog, _ := LoadTransactionLog("")
+			_, err := ApplyIssues(context.Background(), tt.fake.newClient(t), []IssueOutput{output}, ApplyOptions{OnFailure: OnFailureContinue, Log: txLog})
+			var applyErr *ApplyError
+			if !errors.As(err, &applyErr) {
+				t.Fatalf("expected *ApplyError, got %v", err)
+			}
+			if applyErr.Step != tt.fake.failStep {
+				t.Errorf("expected step %s, got %s", tt.fake.failStep, applyErr.Step)
+			}
+			if applyErr.FieldID != tt.expectFieldID {
+				t.Errorf("expected field ID %q, got %q", tt.expectFieldID, applyErr.FieldID)
+			}
+			if applyErr.Title != "Wash My Cat" {
+				t.Errorf("expected title in error, got %q", applyErr.Title)
+			}
+			// The issue URL is kept once the issue has been created
+			if tx := txLog.Get(output.OccurrenceKey); tt.fake.failStep != StepCreateIssue && (tx == nil || tx.IssueURL == "") {
+				t.Errorf("expected the created issue URL in the transaction, got %+v", tx)
+			}
+		})
+	}
+}
+
+func TestApplyIssues(t *testing.T) {
+	existing := testIssueOutput("Already There")
+	existing.Status = StatusExists
+
+	fake := &fakeGitHub{}
+	results, err := ApplyIssues(context.Background(), fake.newClient(t), []IssueOutput{
+		existing,
+		testIssueOutput("First"),
+		testIssueOutput("Second"),
+	}, ApplyOptions{OnFailure: OnFailureContinue})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[0].Title != "First" || results[1].Title != "Second" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+	if len(fake.created) != 2 {
+		t.Errorf("expected 2 issues created, got %d", len(fake.created))
+	}
+
+	// The same occurrence twice in the input is applied once
+	again := &fakeGitHub{}
+	if _, err := ApplyIssues(context.Background(), again.newClient(t), []IssueOutput{
+		testIssueOutput("First"),
+		testIssueOutput("First"),
+	}, ApplyOptions{OnFailure: OnFailureContinue}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(again.created) != 1 {
+		t.Errorf("expected 1 issue created, got %d", len(again.created))
+	}
+}
+
+func TestApplyIssues_AdoptsIssueOfEarlierAttempt(t *testing.T) {
+	output := testIssueOutput("Wash My Cat")
+	// An earlier run failed to create the issue, possibly after GitHub created it
+	failedLog := func() *TransactionLog {
+		txLog, _ := LoadTransactionLog("")
+		tx := txLog.Begin(output)
+		txLog.Update(tx, func(tx *Transaction) {
+			tx.Status, tx.FailedStep, tx.Error = TransactionFailed, StepCreateIssue, "connection reset"
+		})
+		return txLog
+	}
+
+	fake := &fakeGitHub{searchable: []ExistingIssue{
+		{Number: 3, Title: "Wash My Cat", Body: "unrelated", State: "open", URL: "https://github.com/owner/repo/issues/3"},
+		{Number: 7, Title: "Wash My Cat", Body: output.Body, State: "open", URL: "https://github.com/owner/repo/issues/7"},
+	}}
+	results, err := ApplyIssues(context.Background(), fake.newClient(t), nil, ApplyOptions{OnFailure: OnFailureResume, Log: failedLog()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fake.created) != 0 {
+		t.Errorf("expected the issue of the earlier attempt to be used, got %d issues created", len(fake.created))
+	}
+	if len(results) != 1 || results[0].IssueURL != "https://github.com/owner/repo/issues/7" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+	if len(fake.addedItems) != 1 || fake.addedItems[0]["contentId"] != "I_resolved" {
+		t.Errorf("expected the found issue to be added to the project, got %v", fake.addedItems)
+	}
+
+	// A closed issue, such as one closed by rollback, is not reused
+	closed := &fakeGitHub{searchable: []ExistingIssue{
+		{Number: 7, Title: "[rolled back] Wash My Cat", Body: output.Body, State: "closed", URL: "https://github.com/owner/repo/issues/7"},
+	}}
+	if _, err := ApplyIssues(context.Background(), closed.newClient(t), nil, ApplyOptions{OnFailure: OnFailureResume, Log: failedLog()}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(closed.created) != 1 {
+		t.Errorf("expected a new issue, got %d created", len(closed.created))
+	}
+
+	// A new occurrence has no earlier attempt to look for
+	fresh := &fakeGitHub{searchable: fake.searchable}
+	if _, err := ApplyIssues(context.Background(), fresh.newClient(t), []IssueOutput{output}, ApplyOptions{OnFailure: OnFailureResume}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fresh.searches != 0 || len(fresh.created) != 1 {
+		t.Errorf("expected the issue to be created without searching, got %d searches and %d issues", fresh.searches, len(fresh.created))
+	}
+}
+
+// cancelingTransport cancels the run when a GraphQL request containing match is sent, as
+// SIGTERM would in the middle of an issue, and fails that request.
+type cancelingTransport struct {
+	next   http.RoundTripper
+	match  string
+	cancel context.CancelFunc
+}
+
+func (c *cancelingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		data, _ := io.ReadAll(body)
+		if strings.Contains(string(data), c.match) {
+			c.cancel()
+			return nil, context.Canceled
+		}
+	}
+	return c.next.RoundTrip(req)
+}
+
+func TestApplyIssues_RollbackAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fake := &fakeGitHub{}
+	server := httptest.NewServer(http.HandlerFunc(fake.handle))
+	defer server.Close()
+	client := NewGitHubClientWithHTTPClient(&http.Client{Transport: &cancelingTransport{
+		next:   &mockTransport{baseURL: server.URL},
+		match:  "updateProjectV2ItemFieldValue",
+		cancel: cancel,
+	}})
+
+	output := testIssueOutput("Wash My Cat")
+	txLog, _ := LoadTransactionLog("")
+	if _, err := ApplyIssues(ctx, client, []IssueOutput{output}, ApplyOptions{OnFailure: OnFailureRollback, Log: txLog}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if len(fake.deletedItems) != 1 {
+		t.Errorf("expected the project item to be removed, got %v", fake.deletedItems)
+	}
+	if len(fake.updatedIssues) != 1 || fake.updatedIssues[0]["state"] != "closed" {
+		t.Errorf("expected the issue to be closed, got %v", fake.updatedIssues)
+	}
+	if tx := txLog.Get(output.OccurrenceKey); tx.Status != TransactionRolledBack {
+		t.Errorf("expected rolled back transaction, got %s (%s)", tx.Status, tx.Error)
+	}
+}
+
+func TestApplyIssues_Concurrency(t *testing.T) {
+	var outputs []IssueOutput
+	for i := range 12 {
+		outputs = append(outputs, testIssueOutput(fmt.Sprintf("Issue %d", i)))
+	}
+
+	fake := &fakeGitHub{}
+	txLog, err := LoadTransactionLog(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := ApplyIssues(context.Background(), fake.newClient(t), outputs, ApplyOptions{OnFailure: OnFailureContinue, Concurrency: 4, Log: txLog})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fake.created) != len(outputs) {
+		t.Errorf("expected %d issues created, got %d", len(outputs), len(fake.created))
+	}
+	// Results keep the input order whatever order issues finish in
+	for i, result := range results {
+		if result.Title != outputs[i].Title {
+			t.Errorf("results[%d]: expected %q, got %q", i, outputs[i].Title, result.Title)
+		}
+	}
+
+	saved, err := LoadTransactionLog(txLog.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, output := range outputs {
+		if tx := saved.Get(output.OccurrenceKey); tx == nil || tx.Status != TransactionCompleted {
+			t.Errorf("expected a completed transaction for %q, got %+v", output.Title, tx)
+		}
+	}
+}
+
+func TestApplyIssues_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fake := &fakeGitHub{}
+	_, err := ApplyIssues(ctx, fake.newClient(t), []IssueOutput{testIssueOutput("First"), testIssueOutput("Second")}, ApplyOptions{OnFailure: OnFailureContinue, Concurrency: 2})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if len(fake.created) != 0 {
+		t.Errorf("expected no issues created, got %d", len(fake.created))
+	}
+}
+
+func TestApplyIssues_OnFailure(t *testing.T) {
+	outputs := []IssueOutput{testIssueOutput("First"), testIssueOutput("Second")}
+
+	t.Run("continue", func(t *testing.T) {
+		fake := &fakeGitHub{failStep: StepAddProjectItem}
+		txLog, _ := LoadTransactionLog("")
+		results, err := ApplyIssues(context.Background(), fake.newClient(t), outputs, ApplyOptions{OnFailure: OnFailureContinue, Log: txLog})
+		if err == nil || !strings.Contains(err.Error(), "2 of 2 issue(s) failed") {
+			t.Fatalf("expected both issues to fail, got %v", err)
+		}
+		if len(results) != 0 || len(fake.created) != 2 {
+			t.Errorf("expected every issue to be attempted, got %d results and %d issues", len(results), len(fake.created))
+		}
+		tx := txLog.Get(outputs[0].OccurrenceKey)
+		if tx.Status != TransactionFailed || tx.FailedStep != StepAddProjectItem || tx.IssueNumber != 1 {
+			t.Errorf("unexpected transaction: %+v", tx)
+		}
+		if len(fake.updatedIssues) != 0 || len(fake.deletedItems) != 0 {
+			t.Errorf("expected nothing to be rolled back, got %v and %v", fake.updatedIssues, fake.deletedItems)
+		}
+	})
+
+	t.Run("rollback closes the issue and removes the item", func(t *testing.T) {
+		fake := &fakeGitHub{failStep: StepUpdateFieldValue}
+		txLog, _ := LoadTransactionLog("")
+		_, err := ApplyIssues(context.Background(), fake.newClient(t), outputs[:1], ApplyOptions{OnFailure: OnFailureRollback, RollbackIssues: RollbackClose, Log: txLog})
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if len(fake.deletedItems) != 1 || fake.deletedItems[0]["itemId"] != "PVTI_1" {
+			t.Errorf("expected the project item to be removed, got %v", fake.deletedItems)
+		}
+		if len(fake.updatedIssues) != 1 {
+			t.Fatalf("expected the issue to be closed, got %v", fake.updatedIssues)
+		}
+		update := fake.updatedIssues[0]
+		if update["state"] != "closed" || update["state_reason"] != "not_planned" || update["title"] != "[rolled back] First" {
+			t.Errorf("unexpected issue update: %v", update)
+		}
+		if _, ok := OccurrenceKeyOf(update["body"].(string)); ok {
+			t.Error("expected the occurrence marker to be removed from the body")
+		}
+		if tx := txLog.Get(outputs[0].OccurrenceKey); tx.Status != TransactionRolledBack {
+			t.Errorf("expected rolled back transaction, got %s", tx.Status)
+		}
+	})
+
+	t.Run("rollback deletes the issue", func(t *testing.T) {
+		fake := &fakeGitHub{failStep: StepAddProjectItem}
+		if _, err := ApplyIssues(context.Background(), fake.newClient(t), outputs[:1], ApplyOptions{OnFailure: OnFailureRollback, RollbackIssues: RollbackDelete}); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if len(fake.deletedIssues) != 1 || fake.deletedIssues[0]["issueId"] != "I_created" {
+			t.Errorf("expected the issue to be deleted, got %v", fake.deletedIssues)
+		}
+		if len(fake.deletedItems) != 0 {
+			t.Errorf("expected no project item to remove, got %v", fake.deletedItems)
+		}
+	})
+
+	t.Run("resume finishes the missing steps on the next run", func(t *testing.T) {
+		stateFile := filepath.Join(t.TempDir(), "state.json")
+		fake := &fakeGitHub{failStep: StepUpdateFieldValue}
+		client := fake.newClient(t)
+
+		txLog, err := LoadTransactionLog(stateFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ApplyIssues(context.Background(), client, outputs[:1], ApplyOptions{OnFailure: OnFailureResume, Log: txLog}); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		// Next run: GitHub works again, and the filter no longer lists the issue as it exists
+		fake.mu.Lock()
+		fake.failStep = ""
+		fake.mu.Unlock()
+		txLog, err = LoadTransactionLog(stateFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results, err := ApplyIssues(context.Background(), client, nil, ApplyOptions{OnFailure: OnFailureResume, Log: txLog})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(results) != 1 || results[0].IssueURL != "https://github.com/owner/repo/issues/1" {
+			t.Errorf("unexpected results: %+v", results)
+		}
+		if len(fake.created) != 1 || len(fake.addedItems) != 1 {
+			t.Errorf("expected the issue and item to be created once, got %d issues and %d items", len(fake.created), len(fake.addedItems))
+		}
+		if len(fake.fieldValues) != 3 {
+			t.Errorf("expected 3 field updates, got %d", len(fake.fieldValues))
+		}
+
+		// A third run has nothing left to do
+		txLog, _ = LoadTransactionLog(stateFile)
+		if tx := txLog.Get(outputs[0].OccurrenceKey); tx.Status != TransactionCompleted {
+			t.Errorf("expected completed transaction, got %s", tx.Status)
+		}
+		results, err = ApplyIssues(context.Background(), client, outputs[:1], ApplyOptions{OnFailure: OnFailureResume, Log: txLog})
+		if err != nil || len(results) != 0 || len(fake.created) != 1 {
+			t.Errorf("expected nothing to be applied again, got %d results, %d issues, err %v", len(results), len(fake.created), err)
+		}
+	})
+}
+
+func TestLoadIssueOutputs(t *testing.T) {
+	input := `[{"name": "Wash My Cat", "title": "Wash My Cat - 2025-03", "template_file": "t.md", "project_id": "PVT_1", "target_repo": "owner/repo",
+		"field_updates": [{"field_id": "F1", "field_type": "TEXT", "value": "x"}], "occurrence_key": "abcd", "body": "b"}]`
+	outputs, err := LoadIssueOutputs(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(outputs) != 1 || outputs[0].Title != "Wash My Cat - 2025-03" || *outputs[0].TargetRepo != "owner/repo" || len(outputs[0].FieldUpdates) != 1 {
+		t.Errorf("unexpected outputs: %+v", outputs)
+	}
+
+	if _, err := LoadIssueOutputs(strings.NewReader("not json")); err == nil {
+		t.Error("expected error for invalid JSON, got nil")
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// CachingClient is a GitHubClient that fetches the name and fields of each project once,
+// keyed by project ID, and answers later calls from memory. With a cache directory, project
+// metadata is also kept on disk for ttl, so that repeated runs skip the API altogether.
+// Failed lookups are not cached.
+type CachingClient struct {
+	GitHubClient
+
+	dir string
+	ttl time.Duration
+	now func() time.Time
+
+	mu       sync.Mutex
+	projects map[string]*projectCacheEntry
+}
+
+// projectCacheEntry holds what is known about one project. Its mutex is held while fetching,
+// so that concurrent callers wait for one request instead of sending their own.
+type projectCacheEntry struct {
+	mu       sync.Mutex
+	name     *string
+	fields   []ProjectField
+	fieldMap map[string]ProjectField
+}
+
+// cachedProject is the on-disk form of a project's metadata.
+type cachedProject struct {
+	FetchedAt time.Time      `json:"fetched_at"`
+	Name      *string        `json:"name,omitempty"`
+	Fields    []ProjectField `json:"fields,omitempty"`
+}
+
+// NewCachingClient wraps ghClient with an in-memory cache, and a disk cache in dir if it is not empty.
+func NewCachingClient(ghClient GitHubClient, dir string, ttl time.Duration) *CachingClient {
+	return &CachingClient{
+		GitHubClient: ghClient,
+		dir:          dir,
+		ttl:          ttl,
+		now:          time.Now,
+		projects:     make(map[string]*projectCacheEntry),
+	}
+}
+
+func (c *CachingClient) entry(projectID string) *projectCacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.projects[projectID]
+	if !ok {
+		entry = &projectCacheEntry{}
+		c.projects[projectID] = entry
+	}
+	return entry
+}
+
+func (c *CachingClient) GetProjectName(ctx context.Context, projectID string) (string, error) {
+	entry := c.entry(projectID)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if entry.name == nil {
+		if cached, ok := c.load(projectID); ok && cached.Name != nil {
+			entry.name = cached.Name
+		}
+	}
+	if entry.name == nil {
+		name, err := c.GitHubClient.GetProjectName(ctx, projectID)
+		if err != nil {
+			return "", err
+		}
+		entry.name = &name
+		c.store(projectID, func(cached *cachedProject) { cached.Name = &name })
+	}
+	return *entry.name, nil
+}
+
+func (c *CachingClient) GetProjectFields(ctx context.Context, projectID string, owner string) ([]ProjectField, error) {
+	entry := c.entry(projectID)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if err := c.loadFields(ctx, entry, projectID, owner); err != nil {
+		return nil, err
+	}
+	return entry.fields, nil
+}
+
+// fieldMap returns the fields of a project by name, built once per project.
+func (c *CachingClient) fieldMap(ctx context.Context, projectID string, owner string) (map[string]ProjectField, error) {
+	entry := c.entry(projectID)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if err := c.loadFields(ctx, entry, projectID, owner); err != nil {
+		return nil, err
+	}
+	if entry.fieldMap == nil {
+		entry.fieldMap = buildFieldMap(entry.fields)
+	}
+	return entry.fieldMap, nil
+}
+
+// loadFields fills in entry.fields from the disk cache or GitHub. entry.mu must be held.
+func (c *CachingClient) loadFields(ctx context.Context, entry *projectCacheEntry, projectID string, owner string) error {
+	if entry.fields != nil {
+		return nil
+	}
+	if cached, ok := c.load(projectID); ok && cached.Fields != nil {
+		Debugf("Using cached fields of project %s fetched at %s", projectID, cached.FetchedAt.Format(time.RFC3339))
+		entry.fields = cached.Fields
+		return nil
+	}
+	fields, err := c.GitHubClient.GetProjectFields(ctx, projectID, owner)
+	if err != nil {
+		return err
+	}
+	if fields == nil {
+		fields = []ProjectField{}
+	}
+	entry.fields = fields
+	c.store(projectID, func(cached *cachedProject) { cached.Fields = fields })
+	return nil
+}
+
+func (c *CachingClient) cacheFile(projectID string) string {
+	return filepath.Join(c.dir, "project-"+url.PathEscape(projectID)+".json")
+}
+
+// load reads the disk cache of a project, if there is one that has not expired.
+func (c *CachingClient) load(projectID string) (cachedProject, bool) {
+	var cached cachedProject
+	if c.dir == "" {
+		return cached, false
+	}
+	data, err := os.ReadFile(c.cacheFile(projectID))
+	if err != nil {
+		return cached, false
+	}
+	if err := json.Unmarshal(data, &cached); err != nil {
+		Debugf("Ignoring unreadable cache file %s: %v", c.cacheFile(projectID), err)
+		return cached, false
+	}
+	if c.now().Sub(cached.FetchedAt) > c.ttl {
+		return cached, false
+	}
+	return cached, true
+}
+
+// store updates the disk cache of a project. A cache that cannot be written is only logged,
+// as the run does not depend on it.
+func (c *CachingClient) store(projectID string, update func(cached *cachedProject)) {
+	if c.dir == "" {
+		return
+	}
+	cached, ok := c.load(projectID)
+	if !ok {
+		cached = cachedProject{FetchedAt: c.now()}
+	}
+	update(&cached)
+	if err := writeCacheFile(c.dir, c.cacheFile(projectID), cached); err != nil {
+		Debugf("Failed to write cache for project %s: %v", projectID, err)
+	}
+}
+
+func writeCacheFile(dir string, path string, cached cachedProject) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cached, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// ProjectFieldMap returns the fields of a project by name. With a CachingClient the map is
+// built once per project and shared, so callers must not modify it.
+func ProjectFieldMap(ctx context.Context, ghClient GitHubClient, projectID string, owner string) (map[string]ProjectField, error) {
+	if c, ok := ghClient.(*CachingClient); ok {
+		return c.fieldMap(ctx, projectID, owner)
+	}
+	fields, err := ghClient.GetProjectFields(ctx, projectID, owner)
+	if err != nil {
+		return nil, err
+	}
+	return buildFieldMap(fields), nil
+}
+
+func buildFieldMap(fields []ProjectField) map[string]ProjectField {
+	fieldMap := make(map[string]ProjectField, len(fields))
+	for _, field := range fields {
+		fieldMap[field.Name] = field
+		Debugf("Found project field: %s (ID: %s, Type: %s)", field.Name, field.ID, field.DataType)
+	}
+	return fieldMap
+}
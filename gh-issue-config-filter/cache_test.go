@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingGitHubClient counts the project metadata requests reaching the wrapped client.
+type countingGitHubClient struct {
+	GitHubClient
+	mu         sync.Mutex
+	nameCalls  int
+	fieldCalls int
+	fail       bool
+}
+
+func (c *countingGitHubClient) GetProjectName(ctx context.Context, projectID string) (string, error) {
+	c.mu.Lock()
+	c.nameCalls++
+	c.mu.Unlock()
+	if c.fail {
+		return "", errors.New("unavailable")
+	}
+	return c.GitHubClient.GetProjectName(ctx, projectID)
+}
+
+func (c *countingGitHubClient) GetProjectFields(ctx context.Context, projectID string, owner string) ([]ProjectField, error) {
+	c.mu.Lock()
+	c.fieldCalls++
+	c.mu.Unlock()
+	if c.fail {
+		return nil, errors.New("unavailable")
+	}
+	return c.GitHubClient.GetProjectFields(ctx, projectID, owner)
+}
+
+func TestCachingClient(t *testing.T) {
+	fields := []ProjectField{{ID: "PVTFL_1", Name: "Status", DataType: "TEXT"}}
+	config := Config{Defaults: Defaults{ProjectID: "PVT_1", TargetRepo: "owner/repo"}}
+	for _, name := range []string{"first", "second", "third", "fourth"} {
+		config.Issues = append(config.Issues, Issue{
+			Name:           name,
+			CreationMonths: []Month{January},
+			TemplateFile:   stringPtr("template.md"),
+			Fields:         map[string]string{"Status": "Ready"},
+		})
+	}
+
+	counting := &countingGitHubClient{GitHubClient: newMockGitHubClient(fields)}
+	client := NewCachingClient(counting, "", time.Hour)
+	if err := ValidateConfigConcurrently(context.Background(), config, client, 4); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fieldMap, err := ProjectFieldMap(context.Background(), client, "PVT_1", "owner")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fieldMap["Status"].ID != "PVTFL_1" {
+		t.Errorf("unexpected field map: %v", fieldMap)
+	}
+	if counting.nameCalls != 1 || counting.fieldCalls != 1 {
+		t.Errorf("expected one request each for the shared project, got %d name and %d field requests", counting.nameCalls, counting.fieldCalls)
+	}
+}
+
+func TestCachingClient_Errors(t *testing.T) {
+	counting := &countingGitHubClient{GitHubClient: newMockGitHubClient(nil), fail: true}
+	client := NewCachingClient(counting, "", time.Hour)
+	ctx := context.Background()
+
+	if _, err := client.GetProjectFields(ctx, "PVT_1", "owner"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	counting.fail = false
+	if _, err := client.GetProjectFields(ctx, "PVT_1", "owner"); err != nil {
+		t.Fatalf("expected a failed lookup to be retried, got %v", err)
+	}
+	if counting.fieldCalls != 2 {
+		t.Errorf("expected 2 requests, got %d", counting.fieldCalls)
+	}
+}
+
+func TestCachingClient_Disk(t *testing.T) {
+	dir := t.TempDir()
+	fields := []ProjectField{{ID: "PVTFL_1", Name: "Status", DataType: "SINGLE_SELECT", Options: []ProjectFieldOption{{ID: "OPT_1", Name: "Ready"}}}}
+	now := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	newClient := func(counting *countingGitHubClient, at time.Time) *CachingClient {
+		client := NewCachingClient(counting, dir, time.Hour)
+		client.now = func() time.Time { return at }
+		return client
+	}
+
+	first := &countingGitHubClient{GitHubClient: newMockGitHubClient(fields)}
+	client := newClient(first, now)
+	if _, err := client.GetProjectName(ctx, "PVT_1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.GetProjectFields(ctx, "PVT_1", "owner"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A later run within the TTL is served from disk
+	second := &countingGitHubClient{GitHubClient: newMockGitHubClient(fields)}
+	client = newClient(second, now.Add(30*time.Minute))
+	name, err := client.GetProjectName(ctx, "PVT_1")
+	if err != nil || name != "Project PVT_1" {
+		t.Errorf("unexpected name %q (error %v)", name, err)
+	}
+	cached, err := client.GetProjectFields(ctx, "PVT_1", "owner")
+	if err != nil || len(cached) != 1 || cached[0].Options[0].ID != "OPT_1" {
+		t.Errorf("unexpected fields %+v (error %v)", cached, err)
+	}
+	if second.nameCalls != 0 || second.fieldCalls != 0 {
+		t.Errorf("expected no requests within the TTL, got %d name and %d field requests", second.nameCalls, second.fieldCalls)
+	}
+
+	// Once expired, the project is fetched again
+	third := &countingGitHubClient{GitHubClient: newMockGitHubClient(fields)}
+	client = newClient(third, now.Add(2*time.Hour))
+	if _, err := client.GetProjectFields(ctx, "PVT_1", "owner"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if third.fieldCalls != 1 {
+		t.Errorf("expected the expired cache to be refreshed, got %d requests", third.fieldCalls)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Holidays lists non-business days, either inline or from a local iCalendar file.
+type Holidays struct {
+	Dates    []string `yaml:"dates,omitempty"`     // Format: "YYYY-MM-DD"
+	ICalFile *string  `yaml:"ical_file,omitempty"` // Path to an .ics file whose all-day events are holidays
+}
+
+// BlackoutPeriod is an inclusive date range, such as a year-end freeze, treated like holidays.
+type BlackoutPeriod struct {
+	Name  string `yaml:"name,omitempty"`
+	From  string `yaml:"from"`  // Format: "YYYY-MM-DD"
+	Until string `yaml:"until"` // Format: "YYYY-MM-DD"
+}
+
+// Holiday policies for issues scheduled on a holiday or during a blackout period.
+const (
+	OnHolidaySkip     = "skip"
+	OnHolidayNext     = "next"
+	OnHolidayPrevious = "previous"
+)
+
+// maxHolidayShift bounds how far an occurrence may be shifted to reach a business day.
+const maxHolidayShift = 366
+
+// Calendar knows which days are business days.
+// The zero value treats Monday to Friday as business days.
+type Calendar struct {
+	holidays  map[time.Time]bool
+	blackouts []dateRange
+}
+
+type dateRange struct {
+	from  time.Time
+	until time.Time
+}
+
+// NewCalendar builds a calendar from the holidays and blackout periods in defaults.
+// Unparseable dates are skipped; ValidateCalendar reports them.
+func NewCalendar(defaults Defaults) Calendar {
+	calendar := Calendar{holidays: make(map[time.Time]bool)}
+	for _, dateStr := range defaults.Holidays.Dates {
+		if date, err := ParseDate(dateStr); err == nil {
+			calendar.holidays[date] = true
+		}
+	}
+	for _, period := range defaults.BlackoutPeriods {
+		from, errFrom := ParseDate(period.From)
+		until, errUntil := ParseDate(period.Until)
+		if errFrom == nil && errUntil == nil {
+			calendar.blackouts = append(calendar.blackouts, dateRange{from: from, until: until})
+		}
+	}
+	return calendar
+}
+
+// IsHoliday reports whether t is a holiday or falls in a blackout period.
+func (c Calendar) IsHoliday(t time.Time) bool {
+	day := dateOf(t)
+	if c.holidays[day] {
+		return true
+	}
+	for _, blackout := range c.blackouts {
+		if !day.Before(blackout.from) && !day.After(blackout.until) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Calendar) hasHolidays() bool {
+	return len(c.holidays) > 0 || len(c.blackouts) > 0
+}
+
+// IsBusinessDay reports whether t is a weekday that is not a holiday.
+func (c Calendar) IsBusinessDay(t time.Time) bool {
+	return isWeekday(t) && !c.IsHoliday(t)
+}
+
+// loadHolidayFile reads the all-day events of an iCalendar file as holiday dates.
+func loadHolidayFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dates, err := parseICalHolidays(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse iCalendar file %s: %w", path, err)
+	}
+	return dates, nil
+}
+
+// parseICalHolidays returns every date covered by the VEVENTs of an iCalendar stream.
+// DTEND is exclusive as defined by RFC 5545; recurrence rules are not expanded.
+func parseICalHolidays(r io.Reader) ([]string, error) {
+	lines, err := unfoldICalLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var dates []string
+	var start, end time.Time
+	inEvent := false
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		// Drop parameters such as ";VALUE=DATE"
+		name, _, _ = strings.Cut(name, ";")
+
+		switch strings.ToUpper(name) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VEVENT") {
+				inEvent = true
+				start, end = time.Time{}, time.Time{}
+			}
+		case "DTSTART":
+			if inEvent {
+				if start, err = parseICalDate(value); err != nil {
+					return nil, err
+				}
+			}
+		case "DTEND":
+			if inEvent {
+				if end, err = parseICalDate(value); err != nil {
+					return nil, err
+				}
+			}
+		case "END":
+			if !strings.EqualFold(value, "VEVENT") || !inEvent {
+				continue
+			}
+			inEvent = false
+			if start.IsZero() {
+				return nil, fmt.Errorf("VEVENT without DTSTART")
+			}
+			if end.IsZero() || !end.After(start) {
+				end = start.AddDate(0, 0, 1)
+			}
+			for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+				dates = append(dates, day.Format("2006-01-02"))
+			}
+		}
+	}
+	return dates, nil
+}
+
+// unfoldICalLines joins continuation lines (starting with a space or tab) as defined by RFC 5545.
+func unfoldICalLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+func parseICalDate(value string) (time.Time, error) {
+	// Only the date part matters for holidays, so times are truncated.
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date value %q", value)
+	}
+	t, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date value %q", value)
+	}
+	return t, nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendar(t *testing.T) {
+	calendar := NewCalendar(Defaults{
+		Holidays: Holidays{Dates: []string{"2025-01-01", "not-a-date"}},
+		BlackoutPeriods: []BlackoutPeriod{
+			{Name: "Year-end freeze", From: "2025-12-25", Until: "2026-01-05"},
+		},
+	})
+
+	cases := []struct {
+		name          string
+		day           time.Time
+		isHoliday     bool
+		isBusinessDay bool
+	}{
+		{name: "holiday", day: time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC), isHoliday: true, isBusinessDay: false},
+		{name: "weekday", day: time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC), isHoliday: false, isBusinessDay: true},
+		{name: "weekend", day: time.Date(2025, time.January, 4, 0, 0, 0, 0, time.UTC), isHoliday: false, isBusinessDay: false},
+		{name: "blackout start", day: time.Date(2025, time.December, 25, 0, 0, 0, 0, time.UTC), isHoliday: true, isBusinessDay: false},
+		{name: "blackout end", day: time.Date(2026, time.January, 5, 23, 0, 0, 0, time.UTC), isHoliday: true, isBusinessDay: false},
+		{name: "after blackout", day: time.Date(2026, time.January, 6, 0, 0, 0, 0, time.UTC), isHoliday: false, isBusinessDay: true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calendar.IsHoliday(tt.day); got != tt.isHoliday {
+				t.Errorf("IsHoliday: expected %v, got %v", tt.isHoliday, got)
+			}
+			if got := calendar.IsBusinessDay(tt.day); got != tt.isBusinessDay {
+				t.Errorf("IsBusinessDay: expected %v, got %v", tt.isBusinessDay, got)
+			}
+		})
+	}
+}
+
+func TestParseICalHolidays(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20250101",
+		"DTEND;VALUE=DATE:20250102",
+		"SUMMARY:New Year's Day",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20250429",
+		"SUMMARY:Showa Day",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20250503",
+		"DTEND;VALUE=DATE:",
+		" 20250506",
+		"SUMMARY:Golden Week",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	got, err := parseICalHolidays(strings.NewReader(ics))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"2025-01-01", "2025-04-29", "2025-05-03", "2025-05-04", "2025-05-05"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestParseICalHolidays_InvalidDate(t *testing.T) {
+	ics := "BEGIN:VEVENT\nDTSTART;VALUE=DATE:2025-01-01\nEND:VEVENT\n"
+	if _, err := parseICalHolidays(strings.NewReader(ics)); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestLoadConfig_HolidayFile(t *testing.T) {
+	dir := t.TempDir()
+	icsPath := filepath.Join(dir, "holidays.ics")
+	ics := "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;VALUE=DATE:20250101\nEND:VEVENT\nEND:VCALENDAR\n"
+	if err := os.WriteFile(icsPath, []byte(ics), 0o644); err != nil {
+		t.Fatalf("failed to write ics: %v", err)
+	}
+	configPath := filepath.Join(dir, "config.yml")
+	config := "defaults:\n  holidays:\n    dates: [\"2025-12-31\"]\n    ical_file: \"" + icsPath + "\"\n"
+	if err := os.WriteFile(configPath, []byte(config), 0o644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	loaded, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"2025-12-31", "2025-01-01"}
+	if !reflect.DeepEqual(loaded.Defaults.Holidays.Dates, expected) {
+		t.Errorf("expected %v, got %v", expected, loaded.Defaults.Holidays.Dates)
+	}
+}
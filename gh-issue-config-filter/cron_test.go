@@ -0,0 +1,115 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	cases := []struct {
+		name        string
+		expr        string
+		expectError bool
+	}{
+		{name: "every day", expr: "0 9 * * *", expectError: false},
+		{name: "lists and ranges", expr: "0 9 1,15 1-6 *", expectError: false},
+		{name: "steps", expr: "*/15 */2 1-31/2 * *", expectError: false},
+		{name: "names", expr: "0 9 * JAN-MAR MON-FRI", expectError: false},
+		{name: "sunday as 7", expr: "0 9 * * 7", expectError: false},
+		{name: "macro", expr: "@weekly", expectError: false},
+		{name: "too few fields", expr: "0 9 * *", expectError: true},
+		{name: "too many fields", expr: "0 9 * * * *", expectError: true},
+		{name: "out of range", expr: "0 24 * * *", expectError: true},
+		{name: "zero day of month", expr: "0 9 0 * *", expectError: true},
+		{name: "inverted range", expr: "0 9 15-1 * *", expectError: true},
+		{name: "invalid step", expr: "*/0 9 * * *", expectError: true},
+		{name: "invalid name", expr: "0 9 * * FOO", expectError: true},
+		{name: "empty list element", expr: "0 9 1,,2 * *", expectError: true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCron(tt.expr)
+			if tt.expectError && err == nil {
+				t.Errorf("expected error, got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCronSchedule_MatchesDay(t *testing.T) {
+	cases := []struct {
+		name   string
+		expr   string
+		day    time.Time
+		expect bool
+	}{
+		{
+			name:   "every Monday on a Monday",
+			expr:   "0 9 * * MON",
+			day:    time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC),
+			expect: true,
+		},
+		{
+			name:   "every Monday on a Tuesday",
+			expr:   "0 9 * * MON",
+			day:    time.Date(2025, time.March, 11, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+		{
+			name:   "1st and 15th on the 15th",
+			expr:   "0 9 1,15 * *",
+			day:    time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC),
+			expect: true,
+		},
+		{
+			name:   "1st and 15th on the 16th",
+			expr:   "0 9 1,15 * *",
+			day:    time.Date(2025, time.March, 16, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+		{
+			name:   "restricted month",
+			expr:   "0 9 1 JAN,JUL *",
+			day:    time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+		{
+			name:   "day of month or day of week when both restricted",
+			expr:   "0 9 1 * FRI",
+			day:    time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC), // Friday
+			expect: true,
+		},
+		{
+			name:   "sunday as 7",
+			expr:   "0 9 * * 7",
+			day:    time.Date(2025, time.March, 16, 0, 0, 0, 0, time.UTC), // Sunday
+			expect: true,
+		},
+		{
+			name:   "stepped day of week stays unrestricted",
+			expr:   "0 9 1 * */1",
+			day:    time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+		{
+			name:   "monthly macro",
+			expr:   "@monthly",
+			day:    time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+			expect: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := schedule.MatchesDay(tt.day); got != tt.expect {
+				t.Errorf("expected %v, got %v", tt.expect, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// dateFieldLayout is the format of DATE field values, as sent to GitHub.
+const dateFieldLayout = "2006-01-02"
+
+// dateFieldReference is the occurrence date DATE field templates are checked with during validation,
+// when no occurrence is known yet.
+var dateFieldReference = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// ExpandDateField returns the value of a DATE field for an occurrence on date: either an ISO date
+// (YYYY-MM-DD) as is, or a template rendered relative to date. Besides the title template functions,
+// such as {{Year}}, supported template functions are:
+//   - {{Date}} - The occurrence date
+//   - {{AddDays N}} - N days after the occurrence date (before it if N is negative)
+//   - {{AddMonths N}} - N months after the occurrence date, clamped to the end of the month
+//   - {{StartOfMonth}} - The first day of the occurrence month
+//   - {{EndOfMonth}} - The last day of the occurrence month
+func ExpandDateField(value string, date time.Time) (string, error) {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, "{{") {
+		if _, err := time.Parse(dateFieldLayout, value); err != nil {
+			return "", fmt.Errorf("invalid date '%s' (must be YYYY-MM-DD or a template such as {{EndOfMonth}})", value)
+		}
+		return value, nil
+	}
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	format := func(t time.Time) string { return t.Format(dateFieldLayout) }
+	funcMap := template.FuncMap{
+		"Date": func() string {
+			return format(day)
+		},
+		"AddDays": func(days int) string {
+			return format(day.AddDate(0, 0, days))
+		},
+		"AddMonths": func(months int) string {
+			return format(addMonthsClamped(day, months))
+		},
+		"StartOfMonth": func() string {
+			return format(day.AddDate(0, 0, 1-day.Day()))
+		},
+		"EndOfMonth": func() string {
+			return format(time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC))
+		},
+	}
+
+	// Title template functions such as {{Year}} are available too
+	tmpl, err := template.New("date").Funcs(titleTemplateFuncs(day)).Funcs(funcMap).Parse(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse date template '%s': %w", value, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct{}{}); err != nil {
+		return "", fmt.Errorf("failed to execute date template '%s': %w", value, err)
+	}
+
+	expanded := strings.TrimSpace(buf.String())
+	if _, err := time.Parse(dateFieldLayout, expanded); err != nil {
+		return "", fmt.Errorf("date template '%s' renders '%s', which is not a date (YYYY-MM-DD)", value, expanded)
+	}
+	return expanded, nil
+}
+
+// ValidateDateField checks a DATE field value without an occurrence date.
+func ValidateDateField(value string) error {
+	_, err := ExpandDateField(value, dateFieldReference)
+	return err
+}
+
+// addMonthsClamped adds months to day, keeping to the last day of the month when the day
+// does not exist there, so that January 31 plus one month is February 28 or 29.
+func addMonthsClamped(day time.Time, months int) time.Time {
+	firstOfMonth := time.Date(day.Year(), day.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	return firstOfMonth.AddDate(0, 0, min(day.Day(), lastDay)-1)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpandDateField(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	date := time.Date(2025, time.January, 31, 9, 0, 0, 0, tokyo)
+
+	tests := []struct {
+		name         string
+		value        string
+		expected     string
+		errorPattern string
+	}{
+		{name: "ISO date", value: "2025-03-01", expected: "2025-03-01"},
+		{name: "ISO date with spaces", value: " 2025-03-01 ", expected: "2025-03-01"},
+		{name: "occurrence date", value: "{{Date}}", expected: "2025-01-31"},
+		{name: "add days", value: "{{AddDays 14}}", expected: "2025-02-14"},
+		{name: "subtract days", value: "{{AddDays -31}}", expected: "2024-12-31"},
+		{name: "add months clamps to the end of the month", value: "{{AddMonths 1}}", expected: "2025-02-28"},
+		{name: "start of month", value: "{{StartOfMonth}}", expected: "2025-01-01"},
+		{name: "end of month", value: "{{EndOfMonth}}", expected: "2025-01-31"},
+		{name: "title template functions", value: "{{Year}}-12-31", expected: "2025-12-31"},
+		{name: "invalid date", value: "2025-02-30", errorPattern: "invalid date '2025-02-30'"},
+		{name: "not a date", value: "next friday", errorPattern: "must be YYYY-MM-DD or a template"},
+		{name: "unknown function", value: "{{EndOfYear}}", errorPattern: "failed to parse date template"},
+		{name: "missing argument", value: "{{AddDays}}", errorPattern: "failed to execute date template"},
+		{name: "renders something else", value: "due {{EndOfMonth}}", errorPattern: "which is not a date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandDateField(tt.value, date)
+			if tt.errorPattern != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorPattern) {
+					t.Errorf("expected error containing %q, got %v (value %q)", tt.errorPattern, err, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestAddMonthsClamped(t *testing.T) {
+	tests := []struct {
+		day      time.Time
+		months   int
+		expected string
+	}{
+		{day: time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC), months: 1, expected: "2024-02-29"},
+		{day: time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC), months: -1, expected: "2025-02-28"},
+		{day: time.Date(2025, time.November, 15, 0, 0, 0, 0, time.UTC), months: 3, expected: "2026-02-15"},
+	}
+
+	for _, tt := range tests {
+		if got := addMonthsClamped(tt.day, tt.months).Format(dateFieldLayout); got != tt.expected {
+			t.Errorf("%s + %d months: expected %s, got %s", tt.day.Format(dateFieldLayout), tt.months, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type dayUnit int
+
+const (
+	dayUnitDay dayUnit = iota
+	dayUnitBusinessDay
+	dayUnitWeekday
+)
+
+// DaySpec selects a single day within a month, such as "15", "last-day",
+// "2nd-tuesday" or "last-business-day".
+type DaySpec struct {
+	// Ordinal counts from the start of the month (1-31), or from its end when negative (-1 is last).
+	Ordinal int
+	unit    dayUnit
+	weekday time.Weekday
+}
+
+var ordinalWords = map[string]int{
+	"first":  1,
+	"second": 2,
+	"third":  3,
+	"fourth": 4,
+	"fifth":  5,
+	"last":   -1,
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// ParseDaySpec parses a relative-day expression.
+// Accepted forms are a day of month ("15"), or "<ordinal>-<unit>" where ordinal is
+// 1st, 2nd, 3rd, ... or first ... fifth, last, and unit is "day", "business-day" or a weekday name.
+func ParseDaySpec(spec string) (DaySpec, error) {
+	s := strings.ToLower(strings.TrimSpace(spec))
+
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 1 || n > 31 {
+			return DaySpec{}, fmt.Errorf("invalid day %q: day of month must be between 1 and 31", spec)
+		}
+		return DaySpec{Ordinal: n, unit: dayUnitDay}, nil
+	}
+
+	ordinalStr, unitStr, ok := strings.Cut(s, "-")
+	if !ok {
+		return DaySpec{}, fmt.Errorf("invalid day %q: expected a day of month or '<ordinal>-<unit>' (e.g. '2nd-tuesday', 'last-business-day')", spec)
+	}
+
+	ordinal, err := parseOrdinal(ordinalStr)
+	if err != nil {
+		return DaySpec{}, fmt.Errorf("invalid day %q: %w", spec, err)
+	}
+
+	switch unitStr {
+	case "day":
+		if ordinal > 31 {
+			return DaySpec{}, fmt.Errorf("invalid day %q: a month has at most 31 days", spec)
+		}
+		return DaySpec{Ordinal: ordinal, unit: dayUnitDay}, nil
+	case "business-day":
+		if ordinal > 23 {
+			return DaySpec{}, fmt.Errorf("invalid day %q: a month has at most 23 business days", spec)
+		}
+		return DaySpec{Ordinal: ordinal, unit: dayUnitBusinessDay}, nil
+	}
+
+	weekday, ok := weekdayNames[unitStr]
+	if !ok {
+		return DaySpec{}, fmt.Errorf("invalid day %q: unknown unit '%s' (expected 'day', 'business-day' or a weekday name)", spec, unitStr)
+	}
+	if ordinal > 5 {
+		return DaySpec{}, fmt.Errorf("invalid day %q: a month has at most 5 of each weekday", spec)
+	}
+	return DaySpec{Ordinal: ordinal, unit: dayUnitWeekday, weekday: weekday}, nil
+}
+
+func parseOrdinal(s string) (int, error) {
+	if n, ok := ordinalWords[s]; ok {
+		return n, nil
+	}
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		if digits, ok := strings.CutSuffix(s, suffix); ok {
+			n, err := strconv.Atoi(digits)
+			if err != nil || n < 1 {
+				break
+			}
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid ordinal '%s'", s)
+}
+
+// Resolve returns the day selected by the spec in the given month,
+// or false if the month has no such day (e.g. "31" in April, "5th-monday").
+// Business days are taken from calendar.
+func (d DaySpec) Resolve(year int, month time.Month, loc *time.Location, calendar Calendar) (time.Time, bool) {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	daysInMonth := first.AddDate(0, 1, -1).Day()
+
+	var candidates []int
+	for day := 1; day <= daysInMonth; day++ {
+		t := time.Date(year, month, day, 0, 0, 0, 0, loc)
+		switch d.unit {
+		case dayUnitBusinessDay:
+			if !calendar.IsBusinessDay(t) {
+				continue
+			}
+		case dayUnitWeekday:
+			if t.Weekday() != d.weekday {
+				continue
+			}
+		}
+		candidates = append(candidates, day)
+	}
+
+	index := d.Ordinal - 1
+	if d.Ordinal < 0 {
+		index = len(candidates) + d.Ordinal
+	}
+	if index < 0 || index >= len(candidates) {
+		return time.Time{}, false
+	}
+	return time.Date(year, month, candidates[index], 0, 0, 0, 0, loc), true
+}
+
+// Matches reports whether t is the day selected by the spec in the month of t.
+func (d DaySpec) Matches(t time.Time, calendar Calendar) bool {
+	day, ok := d.Resolve(t.Year(), t.Month(), t.Location(), calendar)
+	return ok && day.Day() == t.Day()
+}
+
+func isWeekday(t time.Time) bool {
+	return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDaySpec(t *testing.T) {
+	cases := []struct {
+		name        string
+		spec        string
+		expectError bool
+	}{
+		{name: "day of month", spec: "15", expectError: false},
+		{name: "nth weekday", spec: "2nd-tuesday", expectError: false},
+		{name: "word ordinal", spec: "first-monday", expectError: false},
+		{name: "last weekday", spec: "last-friday", expectError: false},
+		{name: "last day", spec: "last-day", expectError: false},
+		{name: "first business day", spec: "1st-business-day", expectError: false},
+		{name: "last business day", spec: "last-business-day", expectError: false},
+		{name: "case insensitive", spec: "2nd-Tuesday", expectError: false},
+		{name: "day of month out of range", spec: "32", expectError: true},
+		{name: "zero day", spec: "0", expectError: true},
+		{name: "sixth weekday", spec: "6th-monday", expectError: true},
+		{name: "unknown unit", spec: "2nd-fortnight", expectError: true},
+		{name: "invalid ordinal", spec: "twoth-monday", expectError: true},
+		{name: "missing unit", spec: "monday", expectError: true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDaySpec(tt.spec)
+			if tt.expectError && err == nil {
+				t.Errorf("expected error, got nil")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDaySpec_ResolveWithHolidays(t *testing.T) {
+	calendar := NewCalendar(Defaults{Holidays: Holidays{Dates: []string{"2025-03-31"}}})
+	spec, err := ParseDaySpec("last-business-day")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, ok := spec.Resolve(2025, time.March, time.UTC, calendar)
+	if !ok || got.Day() != 28 {
+		t.Errorf("expected March 28, got %v (ok=%v)", got, ok)
+	}
+}
+
+func TestDaySpec_Resolve(t *testing.T) {
+	cases := []struct {
+		name     string
+		spec     string
+		year     int
+		month    time.Month
+		expect   int
+		expectOk bool
+	}{
+		{name: "day of month", spec: "15", year: 2025, month: time.March, expect: 15, expectOk: true},
+		{name: "day missing from month", spec: "31", year: 2025, month: time.April, expectOk: false},
+		{name: "last day of February", spec: "last-day", year: 2024, month: time.February, expect: 29, expectOk: true},
+		{name: "2nd Tuesday", spec: "2nd-tuesday", year: 2025, month: time.March, expect: 11, expectOk: true},
+		{name: "last Friday", spec: "last-friday", year: 2025, month: time.February, expect: 28, expectOk: true},
+		{name: "5th Monday missing", spec: "5th-monday", year: 2025, month: time.February, expectOk: false},
+		{name: "first business day after weekend", spec: "first-business-day", year: 2025, month: time.March, expect: 3, expectOk: true},
+		{name: "last business day before weekend", spec: "last-business-day", year: 2025, month: time.August, expect: 29, expectOk: true},
+		{name: "3rd business day", spec: "3rd-business-day", year: 2025, month: time.March, expect: 5, expectOk: true},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseDaySpec(tt.spec)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, ok := spec.Resolve(tt.year, tt.month, time.UTC, Calendar{})
+			if ok != tt.expectOk {
+				t.Fatalf("expected ok=%v, got %v", tt.expectOk, ok)
+			}
+			if ok && got.Day() != tt.expect {
+				t.Errorf("expected day %d, got %d", tt.expect, got.Day())
+			}
+		})
+	}
+}
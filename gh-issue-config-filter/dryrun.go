@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DryRunCall is a GitHub API call that would have been sent.
+type DryRunCall struct {
+	// API is "rest" or "graphql"
+	API string `json:"api"`
+	// Method and Path are set for REST calls, Query for GraphQL calls
+	Method    string                 `json:"method,omitempty"`
+	Path      string                 `json:"path,omitempty"`
+	Query     string                 `json:"query,omitempty"`
+	Body      map[string]interface{} `json:"body,omitempty"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// DryRunClient is a GitHubClient that records mutations instead of sending them.
+// Reads go to the wrapped client, so project fields are still checked against GitHub.
+type DryRunClient struct {
+	GitHubClient
+	Calls []DryRunCall
+
+	issues int
+	items  int
+}
+
+func NewDryRunClient(ghClient GitHubClient) *DryRunClient {
+	return &DryRunClient{GitHubClient: ghClient}
+}
+
+func (d *DryRunClient) CreateIssue(ctx context.Context, repo Repo, title string, body string) (CreatedIssue, error) {
+	d.issues++
+	d.Calls = append(d.Calls, DryRunCall{
+		API:    "rest",
+		Method: "POST",
+		Path:   fmt.Sprintf("/repos/%s/issues", repo),
+		Body: map[string]interface{}{
+			"title": title,
+			"body":  body,
+		},
+	})
+	return CreatedIssue{
+		Number: d.issues,
+		NodeID: fmt.Sprintf("DRY_RUN_ISSUE_%d", d.issues),
+		URL:    fmt.Sprintf("(dry run) %s issue %d", repo, d.issues),
+	}, nil
+}
+
+func (d *DryRunClient) GetIssueNodeID(ctx context.Context, repo Repo, number int) (string, error) {
+	return fmt.Sprintf("DRY_RUN_ISSUE_%d", number), nil
+}
+
+func (d *DryRunClient) AddProjectItem(ctx context.Context, projectID string, contentID string) (string, error) {
+	d.items++
+	d.Calls = append(d.Calls, DryRunCall{
+		API:       "graphql",
+		Query:     addProjectItemMutation,
+		Variables: addProjectItemVariables(projectID, contentID),
+	})
+	return fmt.Sprintf("DRY_RUN_ITEM_%d", d.items), nil
+}
+
+func (d *DryRunClient) UpdateProjectItemField(ctx context.Context, projectID string, itemID string, update FieldUpdate) error {
+	value, err := fieldValueInput(update)
+	if err != nil {
+		return err
+	}
+	d.Calls = append(d.Calls, DryRunCall{
+		API:       "graphql",
+		Query:     updateProjectItemFieldMutation,
+		Variables: updateProjectItemFieldVariables(projectID, itemID, update.FieldID, value),
+	})
+	return nil
+}
+
+func (d *DryRunClient) UpdateIssue(ctx context.Context, repo Repo, number int, update IssueUpdate) error {
+	body := make(map[string]interface{})
+	data, err := json.Marshal(update)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return err
+	}
+	d.Calls = append(d.Calls, DryRunCall{
+		API:    "rest",
+		Method: "PATCH",
+		Path:   fmt.Sprintf("/repos/%s/issues/%d", repo, number),
+		Body:   body,
+	})
+	return nil
+}
+
+func (d *DryRunClient) DeleteIssue(ctx context.Context, issueID string) error {
+	d.Calls = append(d.Calls, DryRunCall{
+		API:       "graphql",
+		Query:     deleteIssueMutation,
+		Variables: map[string]interface{}{"issueId": issueID},
+	})
+	return nil
+}
+
+func (d *DryRunClient) DeleteProjectItem(ctx context.Context, projectID string, itemID string) error {
+	d.Calls = append(d.Calls, DryRunCall{
+		API:       "graphql",
+		Query:     deleteProjectItemMutation,
+		Variables: map[string]interface{}{"projectId": projectID, "itemId": itemID},
+	})
+	return nil
+}
+
+// WriteDryRun writes the recorded calls as readable text, or as JSON if format is "json".
+func WriteDryRun(w io.Writer, calls []DryRunCall, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(calls)
+	case "text":
+		for i, call := range calls {
+			if i > 0 {
+				if _, err := fmt.Fprintln(w); err != nil {
+					return err
+				}
+			}
+			if err := writeDryRunCall(w, i+1, call); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid format '%s' (must be 'text' or 'json')", format)
+	}
+}
+
+func writeDryRunCall(w io.Writer, n int, call DryRunCall) error {
+	var b strings.Builder
+	if call.API == "rest" {
+		fmt.Fprintf(&b, "#%d REST %s %s\n", n, call.Method, call.Path)
+		body, err := json.MarshalIndent(call.Body, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "%s\n", body)
+	} else {
+		fmt.Fprintf(&b, "#%d GraphQL\n%s\n", n, call.Query)
+		variables, err := json.MarshalIndent(call.Variables, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "variables: %s\n", variables)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ValidateFieldUpdates checks, without changing anything, that every field update refers to
+// a field of its project with the same type, and to an existing option or iteration for
+// SINGLE_SELECT and ITERATION fields.
+func ValidateFieldUpdates(ctx context.Context, ghClient GitHubClient, outputs []IssueOutput) error {
+	fieldsByProject := make(map[string]map[string]ProjectField)
+	for _, output := range outputs {
+		if output.ProjectID == nil || output.TargetRepo == nil {
+			return fmt.Errorf("issue '%s' has no target_repo or project_id", output.Title)
+		}
+		projectID := *output.ProjectID
+
+		fieldMap, ok := fieldsByProject[projectID]
+		if !ok {
+			repo, err := ParseRepo(*output.TargetRepo)
+			if err != nil {
+				return fmt.Errorf("issue '%s': invalid target_repo: %w", output.Title, err)
+			}
+			fields, err := ghClient.GetProjectFields(ctx, projectID, repo.Owner)
+			if err != nil {
+				return fmt.Errorf("failed to get project fields for %s: %w", projectID, err)
+			}
+			fieldMap = make(map[string]ProjectField)
+			for _, field := range fields {
+				fieldMap[field.ID] = field
+			}
+			fieldsByProject[projectID] = fieldMap
+		}
+
+		for _, update := range output.FieldUpdates {
+			field, exists := fieldMap[update.FieldID]
+			if !exists {
+				return fmt.Errorf("issue '%s': field %s does not exist in project %s", output.Title, update.FieldID, projectID)
+			}
+			if field.DataType != update.FieldType {
+				return fmt.Errorf("issue '%s': field '%s' is %s, not %s", output.Title, field.Name, field.DataType, update.FieldType)
+			}
+			if update.OptionID != nil {
+				optionExists := false
+				for _, option := range field.Options {
+					if option.ID == *update.OptionID {
+						optionExists = true
+						break
+					}
+				}
+				if !optionExists {
+					return fmt.Errorf("issue '%s': option %s does not exist in field '%s'", output.Title, *update.OptionID, field.Name)
+				}
+			}
+			if update.IterationID != nil {
+				iterationExists := false
+				for _, iteration := range field.Iterations {
+					if iteration.ID == *update.IterationID {
+						iterationExists = true
+						break
+					}
+				}
+				if !iterationExists {
+					return fmt.Errorf("issue '%s': iteration %s does not exist in field '%s'", output.Title, *update.IterationID, field.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDryRunClient(t *testing.T) {
+	client := NewDryRunClient(newMockGitHubClient(nil))
+	results, err := ApplyIssues(context.Background(), client, []IssueOutput{testIssueOutput("Wash My Cat")}, ApplyOptions{OnFailure: OnFailureContinue})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].ItemID != "DRY_RUN_ITEM_1" {
+		t.Errorf("unexpected results: %+v", results)
+	}
+
+	if len(client.Calls) != 5 {
+		t.Fatalf("expected 5 calls (create, add, 3 field updates), got %d", len(client.Calls))
+	}
+	create := client.Calls[0]
+	if create.API != "rest" || create.Method != "POST" || create.Path != "/repos/owner/repo/issues" || create.Body["title"] != "Wash My Cat" {
+		t.Errorf("unexpected create call: %+v", create)
+	}
+	add := client.Calls[1]
+	if add.Query != addProjectItemMutation || !reflect.DeepEqual(add.Variables, map[string]interface{}{"projectId": "PVT_1", "contentId": "DRY_RUN_ISSUE_1"}) {
+		t.Errorf("unexpected add call: %+v", add)
+	}
+	number := client.Calls[3]
+	if number.Query != updateProjectItemFieldMutation || !reflect.DeepEqual(number.Variables["value"], map[string]interface{}{"number": 3.5}) {
+		t.Errorf("unexpected field update call: %+v", number)
+	}
+	if number.Variables["itemId"] != "DRY_RUN_ITEM_1" || number.Variables["fieldId"] != "PVTF_number" {
+		t.Errorf("unexpected field update variables: %v", number.Variables)
+	}
+}
+
+func TestWriteDryRun(t *testing.T) {
+	client := NewDryRunClient(newMockGitHubClient(nil))
+	if _, err := ApplyIssues(context.Background(), client, []IssueOutput{testIssueOutput("Wash My Cat")}, ApplyOptions{OnFailure: OnFailureContinue}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var text bytes.Buffer
+	if err := WriteDryRun(&text, client.Calls, "text"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"#1 REST POST /repos/owner/repo/issues\n",
+		`"title": "Wash My Cat"`,
+		"#2 GraphQL\nmutation($projectId: ID!, $contentId: ID!) {",
+		`"contentId": "DRY_RUN_ISSUE_1"`,
+		"#5 GraphQL\nmutation($projectId: ID!, $itemId: ID!, $fieldId: ID!, $value: ProjectV2FieldValue!) {",
+		`"singleSelectOptionId": "OPT_1"`,
+	} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("expected text output to contain %q, got:\n%s", want, text.String())
+		}
+	}
+
+	var jsonOut bytes.Buffer
+	if err := WriteDryRun(&jsonOut, client.Calls, "json"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded []DryRunCall
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if len(decoded) != len(client.Calls) || decoded[0].Path != "/repos/owner/repo/issues" {
+		t.Errorf("unexpected JSON output: %+v", decoded)
+	}
+
+	if err := WriteDryRun(&jsonOut, client.Calls, "yaml"); err == nil {
+		t.Error("expected error for an invalid format, got nil")
+	}
+}
+
+func TestValidateFieldUpdates(t *testing.T) {
+	fields := []ProjectField{
+		{ID: "PVTF_text", Name: "Note", DataType: "TEXT"},
+		{ID: "PVTF_number", Name: "Points", DataType: "NUMBER"},
+		{ID: "PVTF_select", Name: "Status", DataType: "SINGLE_SELECT", Options: []ProjectFieldOption{{ID: "OPT_1", Name: "Ready"}}},
+		{ID: "PVTF_sprint", Name: "Sprint", DataType: "ITERATION", Iterations: []ProjectFieldIteration{{ID: "IT_1", Title: "Sprint 1"}}},
+	}
+
+	cases := []struct {
+		name         string
+		updates      []FieldUpdate
+		errorPattern string
+	}{
+		{
+			name:    "valid",
+			updates: testIssueOutput("x").FieldUpdates,
+		},
+		{
+			name:         "unknown field",
+			updates:      []FieldUpdate{{FieldID: "PVTF_gone", FieldType: "TEXT", Value: stringPtr("x")}},
+			errorPattern: "field PVTF_gone does not exist in project PVT_1",
+		},
+		{
+			name:         "type changed",
+			updates:      []FieldUpdate{{FieldID: "PVTF_number", FieldType: "TEXT", Value: stringPtr("x")}},
+			errorPattern: "field 'Points' is NUMBER, not TEXT",
+		},
+		{
+			name:         "unknown option",
+			updates:      []FieldUpdate{{FieldID: "PVTF_select", FieldType: "SINGLE_SELECT", OptionID: stringPtr("OPT_9")}},
+			errorPattern: "option OPT_9 does not exist in field 'Status'",
+		},
+		{
+			name:         "unknown iteration",
+			updates:      []FieldUpdate{{FieldID: "PVTF_sprint", FieldType: "ITERATION", IterationID: stringPtr("IT_9")}},
+			errorPattern: "iteration IT_9 does not exist in field 'Sprint'",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			output := testIssueOutput("Wash My Cat")
+			output.FieldUpdates = tt.updates
+			err := ValidateFieldUpdates(context.Background(), newMockGitHubClient(fields), []IssueOutput{output})
+			if tt.errorPattern == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errorPattern) {
+				t.Errorf("expected error containing %q, got %v", tt.errorPattern, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Policies for issues that already exist in the target repo for their period
+const (
+	ExistingSkip   = "skip"
+	ExistingMark   = "mark"
+	ExistingIgnore = "ignore"
+)
+
+// StatusExists marks an IssueOutput whose issue has already been created.
+const StatusExists = "exists"
+
+// ValidateExistingPolicy checks the value of --existing.
+func ValidateExistingPolicy(policy string) error {
+	switch policy {
+	case ExistingSkip, ExistingMark, ExistingIgnore:
+		return nil
+	default:
+		return fmt.Errorf("invalid value '%s' (must be 'skip', 'mark' or 'ignore')", policy)
+	}
+}
+
+// CheckExistingIssues looks for issues already created in the target repo of each
+// output for its period and drops (skip) or flags (mark) them. outputs must be
+// built from issuesToCreate, in the same order.
+//
+// An issue counts as existing when its body carries the occurrence marker of the output,
+// or when its title equals the rendered title and it was created at or after the start
+// of the period, whether it is still open or not.
+func CheckExistingIssues(ctx context.Context, issuesToCreate IssuesToCreate, outputs []IssueOutput, defaults Defaults, ghClient GitHubClient, policy string) ([]IssueOutput, error) {
+	if policy == ExistingIgnore || len(outputs) == 0 {
+		return outputs, nil
+	}
+	if len(outputs) != len(issuesToCreate.Issues) {
+		return nil, fmt.Errorf("got %d outputs for %d issues", len(outputs), len(issuesToCreate.Issues))
+	}
+
+	// One search per repo, from the earliest period start among its issues
+	since := make(map[string]time.Time)
+	for _, issue := range issuesToCreate.Issues {
+		repo, err := issue.GetTargetRepo(defaults)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get target repo for issue %s: %w", issue.Name, err)
+		}
+		start := issue.PeriodStart(issue.Date)
+		if current, ok := since[repo.String()]; !ok || start.Before(current) {
+			since[repo.String()] = start
+		}
+	}
+
+	existingByRepo := make(map[string][]ExistingIssue)
+	for repo, start := range since {
+		query := fmt.Sprintf("repo:%s is:issue created:>=%s", repo, start.Format("2006-01-02T15:04:05-07:00"))
+		existing, err := ghClient.SearchIssues(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look for existing issues in %s: %w", repo, err)
+		}
+		existingByRepo[repo] = existing
+	}
+
+	checked := make([]IssueOutput, 0, len(outputs))
+	for i, output := range outputs {
+		issue := issuesToCreate.Issues[i]
+		repo, _ := issue.GetTargetRepo(defaults)
+		existing, found := findExistingIssue(existingByRepo[repo.String()], output, issue.PeriodStart(issue.Date))
+		if !found {
+			checked = append(checked, output)
+			continue
+		}
+
+		Debugf("Issue %q already exists for period %s: %s", output.Title, issue.PeriodOf(issue.Date), existing.URL)
+		if policy == ExistingSkip {
+			continue
+		}
+		output.Status = StatusExists
+		output.ExistingIssueURL = existing.URL
+		checked = append(checked, output)
+	}
+
+	return checked, nil
+}
+
+func findExistingIssue(existing []ExistingIssue, output IssueOutput, periodStart time.Time) (ExistingIssue, bool) {
+	for _, issue := range existing {
+		if key, ok := OccurrenceKeyOf(issue.Body); ok && output.OccurrenceKey != "" && key == output.OccurrenceKey {
+			return issue, true
+		}
+	}
+	for _, issue := range existing {
+		if issue.Title == output.Title && !issue.CreatedAt.Before(periodStart) {
+			return issue, true
+		}
+	}
+	return ExistingIssue{}, false
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCheckExistingIssues(t *testing.T) {
+	defaults := Defaults{ProjectID: "default_project_id", TargetRepo: "owner/repo"}
+	now := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.UTC)
+	issuesToCreate := IssuesToCreate{
+		Issues: []IssueToCreate{
+			NewIssueToCreate(Issue{Name: "Monthly Report", CreationMonths: []Month{March}}, defaults, now),
+			NewIssueToCreate(Issue{Name: "Weekly Sync", Schedule: stringPtr("0 9 * * MON")}, defaults, now),
+			NewIssueToCreate(Issue{Name: "Other Repo", CreationMonths: []Month{March}, TargetRepo: stringPtr("other/repo")}, defaults, now),
+			NewIssueToCreate(Issue{Name: "Edited", CreationMonths: []Month{March}, TargetRepo: stringPtr("other/repo")}, defaults, now),
+		},
+	}
+	outputs := []IssueOutput{
+		{Name: "Monthly Report", Title: "Monthly Report", OccurrenceKey: "aaaa"},
+		{Name: "Weekly Sync", Title: "Weekly Sync", OccurrenceKey: "bbbb"},
+		{Name: "Other Repo", Title: "Other Repo", OccurrenceKey: "cccc"},
+		{Name: "Edited", Title: "Edited", OccurrenceKey: "dddd"},
+	}
+	existing := map[string][]ExistingIssue{
+		"owner/repo": {
+			// Created earlier this month: already exists for the monthly period
+			{Number: 1, Title: "Monthly Report", State: "closed", URL: "https://github.com/owner/repo/issues/1", CreatedAt: time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC)},
+			// Created last week: a previous occurrence of the weekly issue
+			{Number: 2, Title: "Weekly Sync", State: "open", URL: "https://github.com/owner/repo/issues/2", CreatedAt: time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC)},
+		},
+		"other/repo": {
+			// Title edited by hand, found by its occurrence marker
+			{Number: 3, Title: "Edited by hand", Body: "body\n\n" + OccurrenceMarker("dddd"), State: "open", URL: "https://github.com/other/repo/issues/3", CreatedAt: time.Date(2025, time.March, 2, 9, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	cases := []struct {
+		name          string
+		policy        string
+		expectTitles  []string
+		expectStatus  []string
+		expectQueries int
+	}{
+		{
+			name:          "skip",
+			policy:        ExistingSkip,
+			expectTitles:  []string{"Weekly Sync", "Other Repo"},
+			expectStatus:  []string{"", ""},
+			expectQueries: 2,
+		},
+		{
+			name:          "mark",
+			policy:        ExistingMark,
+			expectTitles:  []string{"Monthly Report", "Weekly Sync", "Other Repo", "Edited"},
+			expectStatus:  []string{StatusExists, "", "", StatusExists},
+			expectQueries: 2,
+		},
+		{
+			name:          "ignore",
+			policy:        ExistingIgnore,
+			expectTitles:  []string{"Monthly Report", "Weekly Sync", "Other Repo", "Edited"},
+			expectStatus:  []string{"", "", "", ""},
+			expectQueries: 0,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockGitHubClient{existingIssues: existing}
+			got, err := CheckExistingIssues(context.Background(), issuesToCreate, outputs, defaults, client, tt.policy)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.expectTitles) {
+				t.Fatalf("expected %d outputs, got %d: %+v", len(tt.expectTitles), len(got), got)
+			}
+			for i, output := range got {
+				if output.Title != tt.expectTitles[i] {
+					t.Errorf("outputs[%d]: expected title %q, got %q", i, tt.expectTitles[i], output.Title)
+				}
+				if output.Status != tt.expectStatus[i] {
+					t.Errorf("outputs[%d]: expected status %q, got %q", i, tt.expectStatus[i], output.Status)
+				}
+			}
+			if tt.policy == ExistingMark && got[0].ExistingIssueURL != "https://github.com/owner/repo/issues/1" {
+				t.Errorf("expected existing issue URL, got %q", got[0].ExistingIssueURL)
+			}
+			if len(client.searchQueries) != tt.expectQueries {
+				t.Errorf("expected %d searches, got %d: %v", tt.expectQueries, len(client.searchQueries), client.searchQueries)
+			}
+		})
+	}
+}
+
+func TestCheckExistingIssues_SearchesFromEarliestPeriod(t *testing.T) {
+	defaults := Defaults{ProjectID: "default_project_id", TargetRepo: "owner/repo", Timezone: stringPtr("Asia/Tokyo")}
+	now := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+	issuesToCreate := IssuesToCreate{
+		Issues: []IssueToCreate{
+			NewIssueToCreate(Issue{Name: "Weekly Sync", Schedule: stringPtr("0 9 * * MON")}, defaults, now),
+			NewIssueToCreate(Issue{Name: "Monthly Report", CreationMonths: []Month{March}}, defaults, now),
+		},
+	}
+	outputs := []IssueOutput{{Title: "Weekly Sync"}, {Title: "Monthly Report"}}
+
+	client := &mockGitHubClient{}
+	if _, err := CheckExistingIssues(context.Background(), issuesToCreate, outputs, defaults, client, ExistingSkip); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"repo:owner/repo is:issue created:>=2025-03-01T00:00:00+09:00"}
+	if len(client.searchQueries) != 1 || client.searchQueries[0] != expected[0] {
+		t.Errorf("expected queries %v, got %v", expected, client.searchQueries)
+	}
+}
+
+func TestValidateExistingPolicy(t *testing.T) {
+	for _, policy := range []string{ExistingSkip, ExistingMark, ExistingIgnore} {
+		if err := ValidateExistingPolicy(policy); err != nil {
+			t.Errorf("unexpected error for %q: %v", policy, err)
+		}
+	}
+	if err := ValidateExistingPolicy("delete"); err == nil {
+		t.Error("expected error for 'delete', got nil")
+	}
+}
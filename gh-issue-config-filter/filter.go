@@ -1,15 +1,84 @@
 package main
 
-func GetIssuesToCreate(config Config, month Month) IssuesToCreate {
+import (
+	"slices"
+	"time"
+)
+
+func GetIssuesToCreate(config Config, now time.Time) IssuesToCreate {
 	issuesToCreate := IssuesToCreate{
 		Issues: []IssueToCreate{},
 	}
 
+	calendar := NewCalendar(config.Defaults)
 	for _, candidate := range config.Issues {
-		if candidate.IsCreationMonth(month) {
-			issueToCreate := NewIssueToCreate(candidate, config.Defaults)
+		localNow := candidate.InLocation(now, config.Defaults)
+		if candidate.IsActiveOn(localNow) && candidate.IsDueOn(localNow, calendar) {
+			issueToCreate := NewIssueToCreate(candidate, config.Defaults, now)
 			issuesToCreate.Issues = append(issuesToCreate.Issues, issueToCreate)
 		}
 	}
 	return issuesToCreate
 }
+
+// GetIssuesToCreateSince returns every occurrence due after since and up to now,
+// so that runs missed between the two are caught up.
+// Each occurrence carries its own date: the scheduled day, or for month-level schedules
+// the first due day of the month. An occurrence in the period containing since is skipped
+// if it was due by since, as the run at since already created it; one due later in that
+// period, such as after a holiday or when the issue becomes active, is still returned. Occurrences are ordered by date, then by config order.
+func GetIssuesToCreateSince(config Config, since time.Time, now time.Time) IssuesToCreate {
+	return getIssuesToCreateBetween(config, since, now, true)
+}
+
+// GetIssuesToCreateFrom returns every occurrence due from the day of from up to until, like
+// GetIssuesToCreateSince but including the period containing from: an occurrence of a
+// month-level schedule in the month of from falls on from, or on its first due day after from.
+func GetIssuesToCreateFrom(config Config, from time.Time, until time.Time) IssuesToCreate {
+	return getIssuesToCreateBetween(config, from, until, false)
+}
+
+func getIssuesToCreateBetween(config Config, from time.Time, until time.Time, skipFromPeriod bool) IssuesToCreate {
+	issuesToCreate := IssuesToCreate{
+		Issues: []IssueToCreate{},
+	}
+
+	calendar := NewCalendar(config.Defaults)
+	for _, candidate := range config.Issues {
+		localFrom := candidate.InLocation(from, config.Defaults)
+		localUntil := candidate.InLocation(until, config.Defaults)
+
+		seen := map[string]bool{}
+		first := startOfDay(localFrom)
+		if skipFromPeriod {
+			// The run at from only created the occurrence of its period if it was due by then
+			for day := candidate.PeriodStart(localFrom); !day.After(localFrom); day = day.AddDate(0, 0, 1) {
+				if candidate.IsActiveOn(day) && candidate.IsDueOn(day, calendar) {
+					seen[candidate.PeriodOf(localFrom)] = true
+					break
+				}
+			}
+			first = first.AddDate(0, 0, 1)
+		}
+		for day := first; !day.After(localUntil); day = day.AddDate(0, 0, 1) {
+			if !candidate.IsActiveOn(day) || !candidate.IsDueOn(day, calendar) {
+				continue
+			}
+			period := candidate.PeriodOf(day)
+			if seen[period] {
+				continue
+			}
+			seen[period] = true
+			issuesToCreate.Issues = append(issuesToCreate.Issues, NewIssueToCreate(candidate, config.Defaults, day))
+		}
+	}
+
+	slices.SortStableFunc(issuesToCreate.Issues, func(a, b IssueToCreate) int {
+		return a.Date.Compare(b.Date)
+	})
+	return issuesToCreate
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestGetIssuesToCreate(t *testing.T) {
@@ -9,6 +11,10 @@ func TestGetIssuesToCreate(t *testing.T) {
 		ProjectID:  "default_project_id",
 		TargetRepo: "default/repo",
 	}
+	jan13 := time.Date(2025, time.January, 13, 0, 0, 0, 0, time.UTC) // Monday
+	jan14 := time.Date(2025, time.January, 14, 0, 0, 0, 0, time.UTC) // Tuesday
+	jan15 := time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC)
+	feb15 := time.Date(2025, time.February, 15, 0, 0, 0, 0, time.UTC)
 
 	issue1 := Issue{
 		Name:           "Issue 1",
@@ -26,6 +32,21 @@ func TestGetIssuesToCreate(t *testing.T) {
 		Name:           "Issue 2_4",
 		CreationMonths: []Month{February, April},
 	}
+	weekly := Issue{
+		Name:     "Weekly",
+		Schedule: stringPtr("0 9 * * MON"),
+	}
+	biennial := Issue{
+		Name:           "Biennial",
+		CreationMonths: []Month{January},
+		YearParity:     stringPtr("even"),
+	}
+	tokyoWeekly := Issue{
+		Name:     "Tokyo Weekly",
+		Schedule: stringPtr("0 9 * * MON"),
+		Timezone: stringPtr("Asia/Tokyo"),
+	}
+	sundayNightUTC := time.Date(2025, time.January, 12, 20, 0, 0, 0, time.UTC) // Monday 05:00 in Tokyo
 	otherProjectID := "other_project_id"
 	otherRepo := "other/repo"
 	issue_project_repo := Issue{
@@ -38,7 +59,7 @@ func TestGetIssuesToCreate(t *testing.T) {
 	cases := []struct {
 		name           string
 		config         Config
-		month          Month
+		now            time.Time
 		issuesToCreate IssuesToCreate
 	}{
 		{
@@ -47,7 +68,7 @@ func TestGetIssuesToCreate(t *testing.T) {
 				Defaults: defaults,
 				Issues:   []Issue{},
 			},
-			month:          January,
+			now:            jan15,
 			issuesToCreate: IssuesToCreate{},
 		},
 		{
@@ -56,10 +77,10 @@ func TestGetIssuesToCreate(t *testing.T) {
 				Defaults: defaults,
 				Issues:   []Issue{issue1},
 			},
-			month: January,
+			now: jan15,
 			issuesToCreate: IssuesToCreate{
 				Issues: []IssueToCreate{
-					NewIssueToCreate(issue1, defaults),
+					NewIssueToCreate(issue1, defaults, jan15),
 				},
 			},
 		},
@@ -69,11 +90,11 @@ func TestGetIssuesToCreate(t *testing.T) {
 				Defaults: defaults,
 				Issues:   []Issue{issue1, issue1_3, issue2_4},
 			},
-			month: January,
+			now: jan15,
 			issuesToCreate: IssuesToCreate{
 				Issues: []IssueToCreate{
-					NewIssueToCreate(issue1, defaults),
-					NewIssueToCreate(issue1_3, defaults),
+					NewIssueToCreate(issue1, defaults, jan15),
+					NewIssueToCreate(issue1_3, defaults, jan15),
 				},
 			},
 		},
@@ -83,11 +104,11 @@ func TestGetIssuesToCreate(t *testing.T) {
 				Defaults: defaults,
 				Issues:   []Issue{issue2, issue1_3, issue2_4},
 			},
-			month: February,
+			now: feb15,
 			issuesToCreate: IssuesToCreate{
 				Issues: []IssueToCreate{
-					NewIssueToCreate(issue2, defaults),
-					NewIssueToCreate(issue2_4, defaults),
+					NewIssueToCreate(issue2, defaults, feb15),
+					NewIssueToCreate(issue2_4, defaults, feb15),
 				},
 			},
 		},
@@ -97,20 +118,199 @@ func TestGetIssuesToCreate(t *testing.T) {
 				Defaults: defaults,
 				Issues:   []Issue{issue_project_repo},
 			},
-			month: January,
+			now: jan15,
+			issuesToCreate: IssuesToCreate{
+				Issues: []IssueToCreate{
+					NewIssueToCreate(issue_project_repo, defaults, jan15),
+				},
+			},
+		},
+		{
+			name: "Cron schedule on matching day",
+			config: Config{
+				Defaults: defaults,
+				Issues:   []Issue{issue1, weekly},
+			},
+			now: jan13,
+			issuesToCreate: IssuesToCreate{
+				Issues: []IssueToCreate{
+					NewIssueToCreate(issue1, defaults, jan13),
+					NewIssueToCreate(weekly, defaults, jan13),
+				},
+			},
+		},
+		{
+			name: "Cron schedule on other day",
+			config: Config{
+				Defaults: defaults,
+				Issues:   []Issue{weekly},
+			},
+			now:            jan14,
+			issuesToCreate: IssuesToCreate{},
+		},
+		{
+			name: "Year restriction",
+			config: Config{
+				Defaults: defaults,
+				Issues:   []Issue{issue1, biennial},
+			},
+			now: jan15,
 			issuesToCreate: IssuesToCreate{
 				Issues: []IssueToCreate{
-					NewIssueToCreate(issue_project_repo, defaults),
+					NewIssueToCreate(issue1, defaults, jan15),
+				},
+			},
+		},
+		{
+			name: "Timezone decides the day",
+			config: Config{
+				Defaults: defaults,
+				Issues:   []Issue{tokyoWeekly},
+			},
+			now: sundayNightUTC,
+			issuesToCreate: IssuesToCreate{
+				Issues: []IssueToCreate{
+					NewIssueToCreate(tokyoWeekly, defaults, sundayNightUTC),
 				},
 			},
 		},
 	}
 	for _, tt := range cases {
 		t.Run(tt.name, func(t *testing.T) {
-			got := GetIssuesToCreate(tt.config, tt.month)
+			got := GetIssuesToCreate(tt.config, tt.now)
 			if !got.Equals(tt.issuesToCreate) {
 				t.Errorf("expected %v, got %v", tt.issuesToCreate, got)
 			}
 		})
 	}
 }
+
+func TestGetIssuesToCreateSince(t *testing.T) {
+	defaults := Defaults{
+		ProjectID:  "default_project_id",
+		TargetRepo: "default/repo",
+		Holidays:   Holidays{Dates: []string{"2025-03-01"}},
+	}
+	monthly := Issue{
+		Name:           "Monthly",
+		CreationMonths: []Month{January, February, March, April},
+	}
+	weekly := Issue{
+		Name:     "Weekly",
+		Schedule: stringPtr("0 9 * * MON"),
+	}
+
+	cases := []struct {
+		name        string
+		issues      []Issue
+		since       time.Time
+		now         time.Time
+		expectNames []string
+		expectDates []string
+	}{
+		{
+			name:        "missed months are caught up with their own dates",
+			issues:      []Issue{monthly},
+			since:       time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+			now:         time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC),
+			expectNames: []string{"Monthly", "Monthly"},
+			expectDates: []string{"2025-02-01", "2025-03-01"},
+		},
+		{
+			name:        "month containing since is skipped",
+			issues:      []Issue{monthly},
+			since:       time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+			now:         time.Date(2025, time.March, 20, 0, 0, 0, 0, time.UTC),
+			expectNames: []string{},
+			expectDates: []string{},
+		},
+		{
+			name:        "day-level occurrences interleave by date",
+			issues:      []Issue{weekly, monthly},
+			since:       time.Date(2025, time.January, 31, 12, 0, 0, 0, time.UTC),
+			now:         time.Date(2025, time.February, 10, 12, 0, 0, 0, time.UTC),
+			expectNames: []string{"Monthly", "Weekly", "Weekly"},
+			expectDates: []string{"2025-02-01", "2025-02-03", "2025-02-10"},
+		},
+		{
+			name: "month containing since is caught up when not due by since",
+			issues: []Issue{
+				{Name: "Active later", CreationMonths: []Month{March}, ActiveFrom: stringPtr("2025-03-15")},
+				{Name: "Holiday first", CreationMonths: []Month{March}, OnHoliday: stringPtr(OnHolidaySkip)},
+			},
+			since:       time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC),
+			now:         time.Date(2025, time.March, 20, 9, 0, 0, 0, time.UTC),
+			expectNames: []string{"Holiday first", "Active later"},
+			expectDates: []string{"2025-03-02", "2025-03-15"},
+		},
+		{
+			name:        "since day itself is not repeated",
+			issues:      []Issue{weekly},
+			since:       time.Date(2025, time.February, 3, 9, 0, 0, 0, time.UTC),
+			now:         time.Date(2025, time.February, 9, 9, 0, 0, 0, time.UTC),
+			expectNames: []string{},
+			expectDates: []string{},
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{Defaults: defaults, Issues: tt.issues}
+			got := GetIssuesToCreateSince(config, tt.since, tt.now)
+
+			names := []string{}
+			dates := []string{}
+			for _, issue := range got.Issues {
+				names = append(names, issue.Name)
+				dates = append(dates, issue.Date.Format("2006-01-02"))
+			}
+			if !reflect.DeepEqual(names, tt.expectNames) {
+				t.Errorf("expected names %v, got %v", tt.expectNames, names)
+			}
+			if !reflect.DeepEqual(dates, tt.expectDates) {
+				t.Errorf("expected dates %v, got %v", tt.expectDates, dates)
+			}
+		})
+	}
+}
+
+func TestGetIssuesToCreate_CalendarDay(t *testing.T) {
+	// --date and --since dates are the same calendar day in every issue's timezone, even when the
+	// issue's timezone is behind the default one
+	defaults := Defaults{
+		ProjectID:  "default_project_id",
+		TargetRepo: "default/repo",
+		Timezone:   stringPtr("Asia/Tokyo"),
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	issue := Issue{
+		Name:           "March Review",
+		CreationMonths: []Month{March},
+		Timezone:       stringPtr("America/New_York"),
+	}
+	config := Config{Defaults: defaults, Issues: []Issue{issue}}
+	clock := time.Date(2025, time.June, 10, 12, 0, 0, 0, time.UTC)
+
+	now, err := ResolveNow(0, "2025-03-01", "", clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := GetIssuesToCreate(config, now)
+	if len(got.Issues) != 1 {
+		t.Fatalf("expected the March issue on 2025-03-01, got %v", got.Issues)
+	}
+	if expected := time.Date(2025, time.March, 1, 0, 0, 0, 0, newYork); !got.Issues[0].Date.Equal(expected) {
+		t.Errorf("expected the occurrence at %v, got %v", expected, got.Issues[0].Date)
+	}
+
+	since, err := ParseTimestamp("2025-02-28")
+	if err != nil {
+		t.Fatal(err)
+	}
+	caughtUp := GetIssuesToCreateSince(config, since, now)
+	if len(caughtUp.Issues) != 1 || caughtUp.Issues[0].Date.Format("2006-01-02") != "2025-03-01" {
+		t.Errorf("expected the March issue to be caught up on 2025-03-01, got %v", caughtUp.Issues)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// ForecastEntry is an occurrence listed by the forecast command.
+type ForecastEntry struct {
+	Date       time.Time
+	Name       string
+	Title      string
+	TargetRepo string
+	ProjectID  string
+}
+
+// Forecast lists every occurrence due from the day of start over the following months.
+// It works offline: titles are rendered, but project fields are not resolved.
+func Forecast(config Config, start time.Time, months int) ([]ForecastEntry, error) {
+	from, until := forecastRange(start, months)
+	issuesToCreate := GetIssuesToCreateFrom(config, from, until)
+
+	entries := make([]ForecastEntry, 0, len(issuesToCreate.Issues))
+	for _, issue := range issuesToCreate.Issues {
+		title, err := buildTitle(issue)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, ForecastEntry{
+			Date:       issue.Date,
+			Name:       issue.Name,
+			Title:      title,
+			TargetRepo: *issue.TargetRepo,
+			ProjectID:  *issue.ProjectID,
+		})
+	}
+	return entries, nil
+}
+
+// forecastRange returns the first and last instant of a forecast starting on the day of start.
+func forecastRange(start time.Time, months int) (time.Time, time.Time) {
+	from := startOfDay(start)
+	return from, from.AddDate(0, months, 0).Add(-time.Nanosecond)
+}
+
+// WriteForecast prints entries as one table per month, including months without occurrences.
+func WriteForecast(w io.Writer, entries []ForecastEntry, start time.Time, months int) error {
+	from, until := forecastRange(start, months)
+	first, last := YearMonthOf(from), YearMonthOf(until)
+
+	byMonth := make(map[YearMonth][]ForecastEntry)
+	for _, entry := range entries {
+		month := YearMonthOf(entry.Date)
+		byMonth[month] = append(byMonth[month], entry)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for n := 0; n <= first.MonthsUntil(last); n++ {
+		month := YearMonthOf(time.Date(first.Year, time.Month(first.Month)+time.Month(n), 1, 0, 0, 0, 0, time.UTC))
+		if n > 0 {
+			fmt.Fprintln(tw)
+		}
+		fmt.Fprintln(tw, month)
+
+		monthEntries := byMonth[month]
+		if len(monthEntries) == 0 {
+			fmt.Fprintln(tw, "  (nothing scheduled)")
+			continue
+		}
+		fmt.Fprintln(tw, "  DATE\tNAME\tTITLE\tTARGET REPO\tPROJECT")
+		for _, entry := range monthEntries {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", entry.Date.Format("2006-01-02"), entry.Name, entry.Title, entry.TargetRepo, entry.ProjectID)
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestForecast(t *testing.T) {
+	config := Config{
+		Defaults: Defaults{
+			ProjectID:  "default_project_id",
+			TargetRepo: "default/repo",
+		},
+		Issues: []Issue{
+			{
+				Name:           "Quarterly",
+				CreationMonths: []Month{March, June},
+				TitleSuffix:    stringPtr("- {{YearMonth}}"),
+			},
+			{
+				Name:        "Monthly Retro",
+				Day:         stringPtr("2nd-tuesday"),
+				TitlePrefix: stringPtr("[{{Date}}]"),
+				ProjectID:   stringPtr("other_project_id"),
+				TargetRepo:  stringPtr("other/repo"),
+			},
+		},
+	}
+	start := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	entries, err := Forecast(config, start, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []ForecastEntry{
+		{Name: "Quarterly", Title: "Quarterly - 2025-03", TargetRepo: "default/repo", ProjectID: "default_project_id"},
+		{Name: "Monthly Retro", Title: "[2025-03-11] Monthly Retro", TargetRepo: "other/repo", ProjectID: "other_project_id"},
+		{Name: "Monthly Retro", Title: "[2025-04-08] Monthly Retro", TargetRepo: "other/repo", ProjectID: "other_project_id"},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d: %+v", len(expected), len(entries), entries)
+	}
+	for i, entry := range entries {
+		if entry.Name != expected[i].Name || entry.Title != expected[i].Title ||
+			entry.TargetRepo != expected[i].TargetRepo || entry.ProjectID != expected[i].ProjectID {
+			t.Errorf("entry[%d]: expected %+v, got %+v", i, expected[i], entry)
+		}
+	}
+}
+
+func TestForecast_StartsMidMonth(t *testing.T) {
+	config := Config{
+		Defaults: Defaults{ProjectID: "default_project_id", TargetRepo: "default/repo"},
+		Issues: []Issue{
+			{Name: "Quarterly", CreationMonths: []Month{March, June}},
+			{Name: "Mid-month", Day: stringPtr("10")},
+		},
+	}
+	start := time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+	entries, err := Forecast(config, start, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The month the forecast starts in is included, dated at the start day; day-level
+	// occurrences before the start day are not
+	expected := []string{
+		"2025-03-15 Quarterly",
+		"2025-04-10 Mid-month",
+		"2025-05-10 Mid-month",
+		"2025-06-01 Quarterly",
+		"2025-06-10 Mid-month",
+		"2025-07-10 Mid-month",
+	}
+	got := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		got = append(got, entry.Date.Format("2006-01-02")+" "+entry.Name)
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestForecast_InvalidTitleTemplate(t *testing.T) {
+	config := Config{
+		Defaults: Defaults{ProjectID: "default_project_id", TargetRepo: "default/repo"},
+		Issues: []Issue{
+			{Name: "Broken", CreationMonths: []Month{March}, TitleSuffix: stringPtr("{{Invalid}}")},
+		},
+	}
+	if _, err := Forecast(config, time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC), 1); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestWriteForecast(t *testing.T) {
+	start := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	entries := []ForecastEntry{
+		{
+			Date:       time.Date(2025, time.March, 11, 0, 0, 0, 0, time.UTC),
+			Name:       "Monthly Retro",
+			Title:      "[2025-03-11] Monthly Retro",
+			TargetRepo: "owner/repo",
+			ProjectID:  "PVT_xxx",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteForecast(&buf, entries, start, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"2025-03\n",
+		"[2025-03-11] Monthly Retro",
+		"owner/repo",
+		"PVT_xxx",
+		"2025-04\n  (nothing scheduled)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "2025-05") {
+		t.Errorf("expected forecast to stop after 2 months, got:\n%s", got)
+	}
+}
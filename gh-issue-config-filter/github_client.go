@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/google/go-github/v62/github"
 )
@@ -12,6 +13,14 @@ import (
 type GitHubClient interface {
 	GetProjectFields(ctx context.Context, projectID string, owner string) ([]ProjectField, error)
 	GetProjectName(ctx context.Context, projectID string) (string, error)
+	SearchIssues(ctx context.Context, query string) ([]ExistingIssue, error)
+	CreateIssue(ctx context.Context, repo Repo, title string, body string) (CreatedIssue, error)
+	GetIssueNodeID(ctx context.Context, repo Repo, number int) (string, error)
+	AddProjectItem(ctx context.Context, projectID string, contentID string) (string, error)
+	UpdateProjectItemField(ctx context.Context, projectID string, itemID string, update FieldUpdate) error
+	UpdateIssue(ctx context.Context, repo Repo, number int, update IssueUpdate) error
+	DeleteIssue(ctx context.Context, issueID string) error
+	DeleteProjectItem(ctx context.Context, projectID string, itemID string) error
 }
 
 type ProjectField struct {
@@ -19,6 +28,8 @@ type ProjectField struct {
 	Name     string
 	DataType string
 	Options  []ProjectFieldOption
+	// Iterations holds the iterations of ITERATION fields, completed ones included
+	Iterations []ProjectFieldIteration `json:",omitempty"`
 }
 
 type ProjectFieldOption struct {
@@ -26,24 +37,54 @@ type ProjectFieldOption struct {
 	Name string
 }
 
+// ExistingIssue is an issue already present in a repository.
+type ExistingIssue struct {
+	Number    int
+	Title     string
+	Body      string
+	State     string
+	URL       string
+	CreatedAt time.Time
+}
+
+// CreatedIssue is an issue created by CreateIssue.
+type CreatedIssue struct {
+	Number int
+	NodeID string
+	URL    string
+}
+
+// IssueUpdate holds the changes made by UpdateIssue; nil fields are left unchanged.
+type IssueUpdate struct {
+	Title       *string `json:"title,omitempty"`
+	Body        *string `json:"body,omitempty"`
+	State       *string `json:"state,omitempty"`
+	StateReason *string `json:"state_reason,omitempty"`
+}
+
 type githubClient struct {
 	client *github.Client
+	// transport is the retry transport requests go through, if any
+	transport *retryTransport
 }
 
-func NewGitHubClient() (GitHubClient, error) {
+// NewGitHubClient creates a client authenticated with GITHUB_TOKEN, retrying requests
+// that hit rate limits or transient errors as configured by retry.
+func NewGitHubClient(retry RetryOptions) (GitHubClient, error) {
 	token := os.Getenv("GITHUB_TOKEN")
 	if token == "" {
 		return nil, fmt.Errorf("GITHUB_TOKEN environment variable is required")
 	}
+	if err := retry.Validate(); err != nil {
+		return nil, err
+	}
 
 	httpClient := &http.Client{
-		Transport: &tokenTransport{
+		Transport: newRetryTransport(&tokenTransport{
 			token: token,
-		},
+		}, retry),
 	}
-
-	client := github.NewClient(httpClient)
-	return &githubClient{client: client}, nil
+	return NewGitHubClientWithHTTPClient(httpClient), nil
 }
 
 type tokenTransport struct {
@@ -55,118 +96,80 @@ func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	return http.DefaultTransport.RoundTrip(req)
 }
 
+// projectFieldsData is the data of projectFieldsQuery.
+type projectFieldsData struct {
+	Node *struct {
+		Fields *struct {
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+			Nodes []struct {
+				ID       string `json:"id"`
+				Name     string `json:"name"`
+				DataType string `json:"dataType"`
+				Options  []struct {
+					ID   string `json:"id"`
+					Name string `json:"name"`
+				} `json:"options,omitempty"`
+				Configuration *struct {
+					Iterations          []iterationData `json:"iterations"`
+					CompletedIterations []iterationData `json:"completedIterations"`
+				} `json:"configuration,omitempty"`
+			} `json:"nodes"`
+		} `json:"fields"`
+	} `json:"node"`
+}
+
+type iterationData struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	StartDate string `json:"startDate"`
+	Duration  int    `json:"duration"`
+}
+
+// projectNameData is the data of projectNameQuery.
+type projectNameData struct {
+	Node *struct {
+		Title string `json:"title"`
+	} `json:"node"`
+}
+
 func (g *githubClient) GetProjectFields(ctx context.Context, projectID string, owner string) ([]ProjectField, error) {
 	var allFields []ProjectField
 	cursor := ""
 	hasNextPage := true
 
 	for hasNextPage {
-		query := fmt.Sprintf(`
-			query($cursor: String) {
-				node(id: "%s") {
-					... on ProjectV2 {
-						fields(first: 100, after: $cursor) {
-							pageInfo {
-								hasNextPage
-								endCursor
-							}
-							nodes {
-								... on ProjectV2Field {
-									id
-									name
-									dataType
-								}
-								... on ProjectV2SingleSelectField {
-									id
-									name
-									dataType
-									options {
-										id
-										name
-									}
-								}
-							}
-						}
-					}
-				}
-			}
-		`, projectID)
-
-		variables := map[string]interface{}{}
+		variables := map[string]interface{}{"id": projectID}
 		if cursor != "" {
 			variables["cursor"] = cursor
 		}
 
-		var result struct {
-			Data struct {
-				Node struct {
-					Fields struct {
-						PageInfo struct {
-							HasNextPage bool   `json:"hasNextPage"`
-							EndCursor   string `json:"endCursor"`
-						} `json:"pageInfo"`
-						Nodes []struct {
-							ID       string `json:"id"`
-							Name     string `json:"name"`
-							DataType string `json:"dataType"`
-							Options  []struct {
-								ID   string `json:"id"`
-								Name string `json:"name"`
-							} `json:"options,omitempty"`
-						} `json:"nodes"`
-					} `json:"fields"`
-				} `json:"node"`
-			} `json:"data"`
-			Errors []struct {
-				Message string `json:"message"`
-				Type    string `json:"type"`
-			} `json:"errors,omitempty"`
-		}
-
-		req, err := g.client.NewRequest("POST", "/graphql", map[string]interface{}{
-			"query":     query,
-			"variables": variables,
-		})
+		data, err := graphQL[projectFieldsData](ctx, g, projectFieldsQuery, variables)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create GraphQL request: %w", err)
+			return nil, projectQueryError(projectID, err)
 		}
-
-		resp, err := g.client.Do(ctx, req, &result)
-		if err != nil {
-			return nil, fmt.Errorf("failed to execute GraphQL query: %w", err)
+		if data.Node == nil {
+			return nil, fmt.Errorf("project not found (ID: %s)", projectID)
 		}
-		defer resp.Body.Close()
-
-		Debugf("GraphQL response status: %d", resp.StatusCode)
-
-		// Check for GraphQL errors
-		if len(result.Errors) > 0 {
-			errorMessages := make([]string, 0, len(result.Errors))
-			for _, err := range result.Errors {
-				errorMessages = append(errorMessages, fmt.Sprintf("%s: %s", err.Type, err.Message))
-				Debugf("GraphQL error: %s - %s", err.Type, err.Message)
-			}
-			// Provide more helpful error message for NOT_FOUND errors
-			for _, err := range result.Errors {
-				if err.Type == "NOT_FOUND" {
-					return nil, fmt.Errorf("project not found (ID: %s). This may indicate: 1) The project ID is incorrect, 2) The token doesn't have access to this project, or 3) The project belongs to a different organization/user. GraphQL error: %s", projectID, err.Message)
-				}
-			}
-			return nil, fmt.Errorf("GraphQL errors: %v", errorMessages)
+		if data.Node.Fields == nil {
+			return nil, fmt.Errorf("node %s is not a project", projectID)
 		}
+		fields := data.Node.Fields
 
 		Debugf("GraphQL response - hasNextPage: %v, endCursor: %s, nodes count: %d",
-			result.Data.Node.Fields.PageInfo.HasNextPage,
-			result.Data.Node.Fields.PageInfo.EndCursor,
-			len(result.Data.Node.Fields.Nodes))
+			fields.PageInfo.HasNextPage,
+			fields.PageInfo.EndCursor,
+			len(fields.Nodes))
 
-		if len(result.Data.Node.Fields.Nodes) == 0 && cursor == "" {
+		if len(fields.Nodes) == 0 && cursor == "" {
 			// First page is empty - this might indicate a permissions issue
 			Debugf("Warning: No fields found in first page. This might indicate a permissions issue or the project has no custom fields.")
 			Debugf("Project ID: %s, Owner: %s", projectID, owner)
 		}
 
-		for _, node := range result.Data.Node.Fields.Nodes {
+		for _, node := range fields.Nodes {
 			field := ProjectField{
 				ID:       node.ID,
 				Name:     node.Name,
@@ -184,55 +187,279 @@ func (g *githubClient) GetProjectFields(ctx context.Context, projectID string, o
 				}
 			}
 
+			// Add iterations for iteration fields
+			if node.Configuration != nil {
+				for _, it := range append(node.Configuration.CompletedIterations, node.Configuration.Iterations...) {
+					field.Iterations = append(field.Iterations, ProjectFieldIteration{
+						ID:        it.ID,
+						Title:     it.Title,
+						StartDate: it.StartDate,
+						Duration:  it.Duration,
+					})
+				}
+			}
+
 			allFields = append(allFields, field)
 		}
 
-		hasNextPage = result.Data.Node.Fields.PageInfo.HasNextPage
-		cursor = result.Data.Node.Fields.PageInfo.EndCursor
+		hasNextPage = fields.PageInfo.HasNextPage
+		cursor = fields.PageInfo.EndCursor
 	}
 
 	return allFields, nil
 }
 
 func (g *githubClient) GetProjectName(ctx context.Context, projectID string) (string, error) {
-	query := fmt.Sprintf(`
-		query {
-			node(id: "%s") {
-				... on ProjectV2 {
-					title
-				}
-			}
+	data, err := graphQL[projectNameData](ctx, g, projectNameQuery, map[string]interface{}{"id": projectID})
+	if err != nil {
+		return "", projectQueryError(projectID, err)
+	}
+
+	if data.Node == nil {
+		return "", fmt.Errorf("project not found (ID: %s)", projectID)
+	}
+	if data.Node.Title == "" {
+		return "", fmt.Errorf("project name is empty for project ID %s", projectID)
+	}
+
+	return data.Node.Title, nil
+}
+
+// SearchIssues returns every issue matching a GitHub search query such as
+// "repo:owner/name is:issue created:>=2025-03-01".
+func (g *githubClient) SearchIssues(ctx context.Context, query string) ([]ExistingIssue, error) {
+	var issues []ExistingIssue
+	opts := &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 100}}
+
+	for {
+		result, resp, err := g.client.Search.Issues(g.requestContext(ctx), query, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search issues with query %q: %w", query, err)
+		}
+		Debugf("Issue search %q: page %d, %d of %d results", query, opts.Page, len(result.Issues), result.GetTotal())
+
+		for _, issue := range result.Issues {
+			issues = append(issues, ExistingIssue{
+				Number:    issue.GetNumber(),
+				Title:     issue.GetTitle(),
+				Body:      issue.GetBody(),
+				State:     issue.GetState(),
+				URL:       issue.GetHTMLURL(),
+				CreatedAt: issue.GetCreatedAt().Time,
+			})
 		}
-	`, projectID)
 
-	var result struct {
-		Data struct {
-			Node struct {
-				Title string `json:"title"`
-			} `json:"node"`
-		} `json:"data"`
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
 	}
 
-	req, err := g.client.NewRequest("POST", "/graphql", map[string]interface{}{
-		"query": query,
+	return issues, nil
+}
+
+func (g *githubClient) CreateIssue(ctx context.Context, repo Repo, title string, body string) (CreatedIssue, error) {
+	issue, _, err := g.client.Issues.Create(g.requestContext(ctx), repo.Owner, repo.Name, &github.IssueRequest{
+		Title: &title,
+		Body:  &body,
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to create GraphQL request: %w", err)
+		return CreatedIssue{}, fmt.Errorf("failed to create issue in %s: %w", repo, err)
 	}
+	return CreatedIssue{
+		Number: issue.GetNumber(),
+		NodeID: issue.GetNodeID(),
+		URL:    issue.GetHTMLURL(),
+	}, nil
+}
 
-	_, err = g.client.Do(ctx, req, &result)
+func (g *githubClient) GetIssueNodeID(ctx context.Context, repo Repo, number int) (string, error) {
+	issue, _, err := g.client.Issues.Get(g.requestContext(ctx), repo.Owner, repo.Name, number)
 	if err != nil {
-		return "", fmt.Errorf("failed to execute GraphQL query: %w", err)
+		return "", fmt.Errorf("failed to get issue %s#%d: %w", repo, number, err)
+	}
+	if issue.GetNodeID() == "" {
+		return "", fmt.Errorf("issue %s#%d has no node ID", repo, number)
 	}
+	return issue.GetNodeID(), nil
+}
 
-	if result.Data.Node.Title == "" {
-		return "", fmt.Errorf("project name is empty for project ID %s", projectID)
+const addProjectItemMutation = `mutation($projectId: ID!, $contentId: ID!) {
+  addProjectV2ItemById(input: {projectId: $projectId, contentId: $contentId}) {
+    item {
+      id
+    }
+  }
+}`
+
+const updateProjectItemFieldMutation = `mutation($projectId: ID!, $itemId: ID!, $fieldId: ID!, $value: ProjectV2FieldValue!) {
+  updateProjectV2ItemFieldValue(input: {projectId: $projectId, itemId: $itemId, fieldId: $fieldId, value: $value}) {
+    projectV2Item {
+      id
+    }
+  }
+}`
+
+const deleteProjectItemMutation = `mutation($projectId: ID!, $itemId: ID!) {
+  deleteProjectV2Item(input: {projectId: $projectId, itemId: $itemId}) {
+    deletedItemId
+  }
+}`
+
+const deleteIssueMutation = `mutation($issueId: ID!) {
+  deleteIssue(input: {issueId: $issueId}) {
+    clientMutationId
+  }
+}`
+
+func addProjectItemVariables(projectID string, contentID string) map[string]interface{} {
+	return map[string]interface{}{
+		"projectId": projectID,
+		"contentId": contentID,
+	}
+}
+
+func updateProjectItemFieldVariables(projectID string, itemID string, fieldID string, value map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"projectId": projectID,
+		"itemId":    itemID,
+		"fieldId":   fieldID,
+		"value":     value,
+	}
+}
+
+// addProjectItemData is the data of addProjectItemMutation.
+type addProjectItemData struct {
+	AddProjectV2ItemById struct {
+		Item struct {
+			ID string `json:"id"`
+		} `json:"item"`
+	} `json:"addProjectV2ItemById"`
+}
+
+func (g *githubClient) AddProjectItem(ctx context.Context, projectID string, contentID string) (string, error) {
+	data, err := graphQL[addProjectItemData](ctx, g, addProjectItemMutation, addProjectItemVariables(projectID, contentID))
+	if err != nil {
+		return "", err
+	}
+	if data.AddProjectV2ItemById.Item.ID == "" {
+		return "", fmt.Errorf("no project item returned for content %s", contentID)
+	}
+
+	return data.AddProjectV2ItemById.Item.ID, nil
+}
+
+func (g *githubClient) UpdateProjectItemField(ctx context.Context, projectID string, itemID string, update FieldUpdate) error {
+	value, err := fieldValueInput(update)
+	if err != nil {
+		return err
+	}
+	return g.mutate(ctx, updateProjectItemFieldMutation, updateProjectItemFieldVariables(projectID, itemID, update.FieldID, value))
+}
+
+func (g *githubClient) UpdateIssue(ctx context.Context, repo Repo, number int, update IssueUpdate) error {
+	_, _, err := g.client.Issues.Edit(g.requestContext(ctx), repo.Owner, repo.Name, number, &github.IssueRequest{
+		Title:       update.Title,
+		Body:        update.Body,
+		State:       update.State,
+		StateReason: update.StateReason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update issue %s#%d: %w", repo, number, err)
+	}
+	return nil
+}
+
+func (g *githubClient) DeleteIssue(ctx context.Context, issueID string) error {
+	return g.mutate(ctx, deleteIssueMutation, map[string]interface{}{"issueId": issueID})
+}
+
+func (g *githubClient) DeleteProjectItem(ctx context.Context, projectID string, itemID string) error {
+	return g.mutate(ctx, deleteProjectItemMutation, map[string]interface{}{
+		"projectId": projectID,
+		"itemId":    itemID,
+	})
+}
+
+// mutate runs a GraphQL mutation whose result is not needed.
+func (g *githubClient) mutate(ctx context.Context, mutation string, variables map[string]interface{}) error {
+	_, err := graphQL[map[string]interface{}](ctx, g, mutation, variables)
+	return err
+}
+
+// fieldValueInput builds the ProjectV2FieldValue input for a field update.
+func fieldValueInput(update FieldUpdate) (map[string]interface{}, error) {
+	switch update.FieldType {
+	case "TEXT":
+		if update.Value == nil {
+			return nil, fmt.Errorf("field %s: value is required for TEXT fields", update.FieldID)
+		}
+		return map[string]interface{}{"text": *update.Value}, nil
+	case "NUMBER":
+		if update.Number != nil {
+			return map[string]interface{}{"number": *update.Number}, nil
+		}
+		// Output written before numbers were emitted as such carries them as text
+		if update.Value == nil {
+			return nil, fmt.Errorf("field %s: number is required for NUMBER fields", update.FieldID)
+		}
+		number, err := ParseNumberField(*update.Value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", update.FieldID, err)
+		}
+		return map[string]interface{}{"number": number}, nil
+	case "DATE":
+		if update.Value == nil {
+			return nil, fmt.Errorf("field %s: value is required for DATE fields", update.FieldID)
+		}
+		if _, err := time.Parse(dateFieldLayout, *update.Value); err != nil {
+			return nil, fmt.Errorf("field %s: invalid date '%s' (must be YYYY-MM-DD)", update.FieldID, *update.Value)
+		}
+		return map[string]interface{}{"date": *update.Value}, nil
+	case "SINGLE_SELECT":
+		if update.OptionID == nil {
+			return nil, fmt.Errorf("field %s: option_id is required for SINGLE_SELECT fields", update.FieldID)
+		}
+		return map[string]interface{}{"singleSelectOptionId": *update.OptionID}, nil
+	case "ITERATION":
+		if update.IterationID == nil {
+			return nil, fmt.Errorf("field %s: iteration_id is required for ITERATION fields", update.FieldID)
+		}
+		return map[string]interface{}{"iterationId": *update.IterationID}, nil
+	default:
+		return nil, fmt.Errorf("field %s: unsupported field type '%s'", update.FieldID, update.FieldType)
 	}
+}
 
-	return result.Data.Node.Title, nil
+// FindIssueByOccurrenceKey looks for an issue in repo whose body carries the occurrence
+// marker for key, so an occurrence is found even after its title was edited.
+func FindIssueByOccurrenceKey(ctx context.Context, ghClient GitHubClient, repo Repo, key string) (ExistingIssue, bool, error) {
+	query := fmt.Sprintf("repo:%s is:issue in:body %q", repo, key)
+	issues, err := ghClient.SearchIssues(ctx, query)
+	if err != nil {
+		return ExistingIssue{}, false, err
+	}
+	// The search is full text, so confirm the marker itself is present
+	for _, issue := range issues {
+		if found, ok := OccurrenceKeyOf(issue.Body); ok && found == key {
+			return issue, true, nil
+		}
+	}
+	return ExistingIssue{}, false, nil
 }
 
 func NewGitHubClientWithHTTPClient(httpClient *http.Client) GitHubClient {
 	client := github.NewClient(httpClient)
-	return &githubClient{client: client}
+	transport, _ := httpClient.Transport.(*retryTransport)
+	return &githubClient{client: client, transport: transport}
+}
+
+// requestContext returns the context for a request through go-github. go-github refuses to
+// send requests while its last response shows the primary rate limit used up; while the
+// transport waits for that limit to reset, go-github is told to wait for it as well.
+func (g *githubClient) requestContext(ctx context.Context) context.Context {
+	if g.transport == nil || !g.transport.paused() {
+		return ctx
+	}
+	return context.WithValue(ctx, github.SleepUntilPrimaryRateLimitResetWhenRateLimited, true)
 }
@@ -3,9 +3,14 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestGetProjectFields(t *testing.T) {
@@ -244,3 +249,376 @@ func (m *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	req.URL.Host = host
 	return http.DefaultTransport.RoundTrip(req)
 }
+
+func TestSearchIssues(t *testing.T) {
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search/issues" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if q := r.URL.Query().Get("q"); q != "repo:owner/repo is:issue" {
+			t.Errorf("unexpected query %q", q)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"total_count": 2,
+				"items": []interface{}{
+					map[string]interface{}{"number": 2, "title": "Second", "state": "closed", "created_at": "2025-03-02T00:00:00Z"},
+				},
+			})
+			return
+		}
+		w.Header().Set("Link", `<`+serverURL+`/search/issues?q=repo%3Aowner%2Frepo+is%3Aissue&page=2>; rel="next"`)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"total_count": 2,
+			"items": []interface{}{
+				map[string]interface{}{"number": 1, "title": "First", "state": "open", "html_url": "https://github.com/owner/repo/issues/1", "created_at": "2025-03-01T00:00:00Z"},
+			},
+		})
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	client := NewGitHubClientWithHTTPClient(&http.Client{Transport: &mockTransport{baseURL: server.URL}})
+	issues, err := client.SearchIssues(context.Background(), "repo:owner/repo is:issue")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("expected 2 issues, got %d", len(issues))
+	}
+	if issues[0].Number != 1 || issues[0].Title != "First" || issues[0].URL != "https://github.com/owner/repo/issues/1" {
+		t.Errorf("unexpected first issue: %+v", issues[0])
+	}
+	if issues[1].Number != 2 || issues[1].State != "closed" || issues[1].CreatedAt.Day() != 2 {
+		t.Errorf("unexpected second issue: %+v", issues[1])
+	}
+}
+
+func TestFieldValueInput(t *testing.T) {
+	cases := []struct {
+		name        string
+		update      FieldUpdate
+		expect      map[string]interface{}
+		expectError bool
+	}{
+		{
+			name:   "text",
+			update: FieldUpdate{FieldID: "F1", FieldType: "TEXT", Value: stringPtr("hello")},
+			expect: map[string]interface{}{"text": "hello"},
+		},
+		{
+			name:   "number",
+			update: FieldUpdate{FieldID: "F2", FieldType: "NUMBER", Number: float64Ptr(2.5)},
+			expect: map[string]interface{}{"number": 2.5},
+		},
+		{
+			name:   "number is sent as a number",
+			update: FieldUpdate{FieldID: "F2", FieldType: "NUMBER", Value: stringPtr("5")},
+			expect: map[string]interface{}{"number": 5.0},
+		},
+		{
+			name:        "invalid number",
+			update:      FieldUpdate{FieldID: "F2", FieldType: "NUMBER", Value: stringPtr("five")},
+			expectError: true,
+		},
+		{
+			name:   "single select",
+			update: FieldUpdate{FieldID: "F3", FieldType: "SINGLE_SELECT", OptionID: stringPtr("OPT_1")},
+			expect: map[string]interface{}{"singleSelectOptionId": "OPT_1"},
+		},
+		{
+			name:        "single select without option",
+			update:      FieldUpdate{FieldID: "F3", FieldType: "SINGLE_SELECT"},
+			expectError: true,
+		},
+		{
+			name:   "date",
+			update: FieldUpdate{FieldID: "F5", FieldType: "DATE", Value: stringPtr("2025-03-31")},
+			expect: map[string]interface{}{"date": "2025-03-31"},
+		},
+		{
+			name:        "invalid date",
+			update:      FieldUpdate{FieldID: "F5", FieldType: "DATE", Value: stringPtr("{{EndOfMonth}}")},
+			expectError: true,
+		},
+		{
+			name:   "iteration",
+			update: FieldUpdate{FieldID: "F6", FieldType: "ITERATION", IterationID: stringPtr("IT_1")},
+			expect: map[string]interface{}{"iterationId": "IT_1"},
+		},
+		{
+			name:        "iteration without iteration ID",
+			update:      FieldUpdate{FieldID: "F6", FieldType: "ITERATION"},
+			expectError: true,
+		},
+		{
+			name:        "unsupported type",
+			update:      FieldUpdate{FieldID: "F4", FieldType: "ASSIGNEES"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fieldValueInput(tt.update)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("expected %v, got %v", tt.expect, got)
+			}
+		})
+	}
+}
+
+// newGraphQLTestClient serves response to every GraphQL request and records the request bodies.
+func newGraphQLTestClient(t *testing.T, response map[string]interface{}) (GitHubClient, *[]map[string]interface{}) {
+	t.Helper()
+	var requests []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		requests = append(requests, body)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+	return NewGitHubClientWithHTTPClient(&http.Client{Transport: &mockTransport{baseURL: server.URL}}), &requests
+}
+
+func TestProjectQueries(t *testing.T) {
+	notFound := map[string]interface{}{
+		"data":   map[string]interface{}{"node": nil},
+		"errors": []interface{}{map[string]interface{}{"type": "NOT_FOUND", "message": "Could not resolve to a node with the global id of 'PVT_x'"}},
+	}
+	forbidden := map[string]interface{}{"type": "FORBIDDEN", "message": "Resource not accessible by integration"}
+
+	tests := []struct {
+		name          string
+		query         func(ctx context.Context, client GitHubClient, projectID string) (interface{}, error)
+		response      map[string]interface{}
+		expected      interface{}
+		errorPatterns []string
+	}{
+		{
+			name:     "name",
+			query:    getProjectName,
+			response: map[string]interface{}{"data": map[string]interface{}{"node": map[string]interface{}{"title": "Backlog"}}},
+			expected: "Backlog",
+		},
+		{
+			name:          "name not found",
+			query:         getProjectName,
+			response:      notFound,
+			errorPatterns: []string{"project not found (ID: ", "NOT_FOUND: Could not resolve"},
+		},
+		{
+			name:  "name with partial data",
+			query: getProjectName,
+			response: map[string]interface{}{
+				"data":   map[string]interface{}{"node": map[string]interface{}{"title": "Backlog"}},
+				"errors": []interface{}{forbidden},
+			},
+			errorPatterns: []string{"FORBIDDEN: Resource not accessible by integration"},
+		},
+		{
+			name:          "name of a null node",
+			query:         getProjectName,
+			response:      map[string]interface{}{"data": map[string]interface{}{"node": nil}},
+			errorPatterns: []string{"project not found (ID: "},
+		},
+		{
+			name:          "name without data",
+			query:         getProjectName,
+			response:      map[string]interface{}{},
+			errorPatterns: []string{"GraphQL response has no data"},
+		},
+		{
+			name:          "fields not found",
+			query:         getProjectFields,
+			response:      notFound,
+			errorPatterns: []string{"project not found (ID: ", "NOT_FOUND: Could not resolve"},
+		},
+		{
+			name:  "fields with partial data",
+			query: getProjectFields,
+			response: map[string]interface{}{
+				"data": map[string]interface{}{"node": map[string]interface{}{"fields": map[string]interface{}{
+					"pageInfo": map[string]interface{}{"hasNextPage": false},
+					"nodes":    []interface{}{map[string]interface{}{"id": "PVTF_1", "name": "Note", "dataType": "TEXT"}, nil},
+				}}},
+				"errors": []interface{}{forbidden},
+			},
+			errorPatterns: []string{"FORBIDDEN: Resource not accessible by integration"},
+		},
+		{
+			name:          "fields of a node that is not a project",
+			query:         getProjectFields,
+			response:      map[string]interface{}{"data": map[string]interface{}{"node": map[string]interface{}{}}},
+			errorPatterns: []string{"is not a project"},
+		},
+	}
+
+	// A project ID that would break out of a string literal in the query
+	projectID := `PVT_1") { id } #`
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, requests := newGraphQLTestClient(t, tt.response)
+			result, err := tt.query(context.Background(), client, projectID)
+
+			if len(*requests) == 0 {
+				t.Fatal("expected a GraphQL request")
+			}
+			request := (*requests)[0]
+			if query, _ := request["query"].(string); strings.Contains(query, projectID) || !strings.Contains(query, "$id: ID!") {
+				t.Errorf("expected the project ID to be passed as $id, got query %q", query)
+			}
+			if variables, _ := request["variables"].(map[string]interface{}); variables["id"] != projectID {
+				t.Errorf("expected variable id %q, got %v", projectID, request["variables"])
+			}
+
+			if len(tt.errorPatterns) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !reflect.DeepEqual(result, tt.expected) {
+					t.Errorf("expected %v, got %v", tt.expected, result)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error, got result %v", result)
+			}
+			for _, pattern := range tt.errorPatterns {
+				if !strings.Contains(err.Error(), pattern) {
+					t.Errorf("expected error containing %q, got %v", pattern, err)
+				}
+			}
+		})
+	}
+}
+
+func getProjectName(ctx context.Context, client GitHubClient, projectID string) (interface{}, error) {
+	return client.GetProjectName(ctx, projectID)
+}
+
+func getProjectFields(ctx context.Context, client GitHubClient, projectID string) (interface{}, error) {
+	return client.GetProjectFields(ctx, projectID, "owner")
+}
+
+func TestGetProjectFields_Iterations(t *testing.T) {
+	client, _ := newGraphQLTestClient(t, map[string]interface{}{
+		"data": map[string]interface{}{"node": map[string]interface{}{"fields": map[string]interface{}{
+			"pageInfo": map[string]interface{}{"hasNextPage": false},
+			"nodes": []interface{}{
+				map[string]interface{}{
+					"id":       "PVTIF_1",
+					"name":     "Sprint",
+					"dataType": "ITERATION",
+					"configuration": map[string]interface{}{
+						"iterations": []interface{}{
+							map[string]interface{}{"id": "IT_2", "title": "Sprint 2", "startDate": "2025-02-17", "duration": 14},
+						},
+						"completedIterations": []interface{}{
+							map[string]interface{}{"id": "IT_1", "title": "Sprint 1", "startDate": "2025-02-03", "duration": 14},
+						},
+					},
+				},
+			},
+		}}},
+	})
+
+	fields, err := client.GetProjectFields(context.Background(), "PVT_1", "owner")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []ProjectField{{
+		ID:       "PVTIF_1",
+		Name:     "Sprint",
+		DataType: "ITERATION",
+		Iterations: []ProjectFieldIteration{
+			{ID: "IT_1", Title: "Sprint 1", StartDate: "2025-02-03", Duration: 14},
+			{ID: "IT_2", Title: "Sprint 2", StartDate: "2025-02-17", Duration: 14},
+		},
+	}}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected %+v, got %+v", expected, fields)
+	}
+}
+
+func TestGitHubClient_RateLimitUsedUp(t *testing.T) {
+	tests := []struct {
+		name             string
+		resetIn          time.Duration
+		expectedRequests int
+		expectError      bool
+	}{
+		{
+			// go-github waits for the reset as well, so keep it close
+			name:             "waits for a reset within max wait",
+			resetIn:          time.Second,
+			expectedRequests: 2,
+		},
+		{
+			name:             "fails without waiting beyond max wait",
+			resetIn:          time.Hour,
+			expectedRequests: 1,
+			expectError:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reset := time.Now().Add(tt.resetIn).Unix()
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				remaining := "4999"
+				if requests == 1 {
+					// The first request succeeds but uses up the rate limit
+					remaining = "0"
+				}
+				w.Header().Set("X-RateLimit-Limit", "5000")
+				w.Header().Set("X-RateLimit-Remaining", remaining)
+				w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"data":{"node":{"title":"Backlog"}}}`)
+			}))
+			defer server.Close()
+
+			transport := newRetryTransport(&mockTransport{baseURL: server.URL}, DefaultRetryOptions())
+			// go-github has waited in real time by the time the transport would
+			transport.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+			client := NewGitHubClientWithHTTPClient(&http.Client{Transport: transport})
+
+			if _, err := client.GetProjectName(context.Background(), "PVT_1"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			name, err := client.GetProjectName(context.Background(), "PVT_1")
+			if tt.expectError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if name != "Backlog" {
+				t.Errorf("expected 'Backlog', got %q", name)
+			}
+			if requests != tt.expectedRequests {
+				t.Errorf("expected %d requests to reach the server, got %d", tt.expectedRequests, requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// projectFieldsQuery lists the fields of a project, 100 per page.
+const projectFieldsQuery = `query($id: ID!, $cursor: String) {
+  node(id: $id) {
+    ... on ProjectV2 {
+      fields(first: 100, after: $cursor) {
+        pageInfo {
+          hasNextPage
+          endCursor
+        }
+        nodes {
+          ... on ProjectV2Field {
+            id
+            name
+            dataType
+          }
+          ... on ProjectV2SingleSelectField {
+            id
+            name
+            dataType
+            options {
+              id
+              name
+            }
+          }
+          ... on ProjectV2IterationField {
+            id
+            name
+            dataType
+            configuration {
+              iterations {
+                ...iteration
+              }
+              completedIterations {
+                ...iteration
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}
+
+fragment iteration on ProjectV2IterationFieldIteration {
+  id
+  title
+  startDate
+  duration
+}`
+
+// projectNameQuery gets the title of a project.
+const projectNameQuery = `query($id: ID!) {
+  node(id: $id) {
+    ... on ProjectV2 {
+      title
+    }
+  }
+}`
+
+type graphQLError struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+// graphQLErrors is the errors[] of a GraphQL response.
+type graphQLErrors []graphQLError
+
+func (e graphQLErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, fmt.Sprintf("%s: %s", err.Type, err.Message))
+	}
+	return fmt.Sprintf("GraphQL errors: [%s]", strings.Join(messages, " "))
+}
+
+// hasType reports whether any of the errors is of the given type, such as NOT_FOUND.
+func (e graphQLErrors) hasType(errorType string) bool {
+	for _, err := range e {
+		if err.Type == errorType {
+			return true
+		}
+	}
+	return false
+}
+
+// graphQLErrorsToError returns the errors[] of a GraphQL response as one error, or nil.
+func graphQLErrorsToError(errs []graphQLError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	for _, err := range errs {
+		Debugf("GraphQL error: %s - %s", err.Type, err.Message)
+	}
+	return graphQLErrors(errs)
+}
+
+// graphQLResponse is the envelope of every GraphQL response, with T the shape of data.
+type graphQLResponse[T any] struct {
+	Data   *T             `json:"data"`
+	Errors []graphQLError `json:"errors,omitempty"`
+}
+
+// graphQL sends a query or mutation with its variables and decodes data into T.
+// Values are only ever passed as variables, never spliced into the query.
+// A response with errors[] is an error even if it carries partial data, as some of
+// the data asked for is then missing.
+func graphQL[T any](ctx context.Context, g *githubClient, query string, variables map[string]interface{}) (T, error) {
+	var data T
+	var result graphQLResponse[T]
+
+	req, err := g.client.NewRequest("POST", "/graphql", map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return data, fmt.Errorf("failed to create GraphQL request: %w", err)
+	}
+
+	resp, err := g.client.Do(g.requestContext(ctx), req, &result)
+	if err != nil {
+		return data, fmt.Errorf("failed to execute GraphQL request: %w", err)
+	}
+	defer resp.Body.Close()
+	Debugf("GraphQL response status: %d", resp.StatusCode)
+
+	if err := graphQLErrorsToError(result.Errors); err != nil {
+		return data, err
+	}
+	if result.Data == nil {
+		return data, errors.New("GraphQL response has no data")
+	}
+	return *result.Data, nil
+}
+
+// projectQueryError explains the usual causes of a project that cannot be found.
+func projectQueryError(projectID string, err error) error {
+	var errs graphQLErrors
+	if errors.As(err, &errs) && errs.hasType("NOT_FOUND") {
+		return fmt.Errorf("project not found (ID: %s). This may indicate: 1) The project ID is incorrect, 2) The token doesn't have access to this project, or 3) The project belongs to a different organization/user. %w", projectID, err)
+	}
+	return err
+}
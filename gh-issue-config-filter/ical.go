@@ -0,0 +1,279 @@
+package main
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// icalDateStamp is the DTSTAMP format (UTC date-time) of RFC 5545.
+const icalDateStamp = "20060102T150405Z"
+
+// icalProductID identifies the generator in exported calendars.
+const icalProductID = "-//Rindrics//recurring-backlog-item-creator//EN"
+
+// icalEvent is an all-day VEVENT, recurring when RRule is set.
+type icalEvent struct {
+	UID         string
+	Date        time.Time
+	Summary     string
+	Description string
+	RRule       string
+}
+
+// ExportICal writes the schedule of every issue as an RFC 5545 calendar.
+// Issues whose schedule and title can be expressed by a recurrence rule become a
+// single recurring event; the others are expanded into one event per occurrence
+// from the day of start over the following months. stamp is used as DTSTAMP.
+func ExportICal(w io.Writer, config Config, start time.Time, months int, stamp time.Time) error {
+	from, until := forecastRange(start, months)
+	calendar := NewCalendar(config.Defaults)
+
+	var events []icalEvent
+	for _, issue := range config.Issues {
+		single := Config{Defaults: config.Defaults, Issues: []Issue{issue}}
+		occurrences := GetIssuesToCreateFrom(single, from, until).Issues
+		if len(occurrences) == 0 {
+			Debugf("no occurrence of issue %s to export", issue.Name)
+			continue
+		}
+
+		description := ""
+		if issue.TemplateFile != nil {
+			description = *issue.TemplateFile
+		}
+
+		rrule, ok := issue.RRule(calendar)
+		// Month-level rules fall on the 1st, so DTSTART must too (it may not with active_from)
+		if ok && (issue.IsDayLevel() || occurrences[0].Date.Day() == 1) {
+			ok, err := titleIsDateIndependent(occurrences[0])
+			if err != nil {
+				return err
+			}
+			if ok {
+				title, err := buildTitle(occurrences[0])
+				if err != nil {
+					return err
+				}
+				events = append(events, icalEvent{
+					UID:         icalUID(issue.Name, "rrule"),
+					Date:        occurrences[0].Date,
+					Summary:     title,
+					Description: description,
+					RRule:       rrule,
+				})
+				continue
+			}
+		}
+
+		for _, occurrence := range occurrences {
+			title, err := buildTitle(occurrence)
+			if err != nil {
+				return err
+			}
+			events = append(events, icalEvent{
+				UID:         icalUID(issue.Name, occurrence.Date.Format("2006-01-02")),
+				Date:        occurrence.Date,
+				Summary:     title,
+				Description: description,
+			})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Date.Before(events[j].Date)
+	})
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + icalProductID,
+		"CALSCALE:GREGORIAN",
+	}
+	for _, event := range events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+event.UID,
+			"DTSTAMP:"+stamp.UTC().Format(icalDateStamp),
+			"DTSTART;VALUE=DATE:"+event.Date.Format("20060102"),
+			"SUMMARY:"+escapeICalText(event.Summary),
+		)
+		if event.Description != "" {
+			lines = append(lines, "DESCRIPTION:"+escapeICalText(event.Description))
+		}
+		if event.RRule != "" {
+			lines = append(lines, "RRULE:"+event.RRule)
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, foldICalLine(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RRule returns the RFC 5545 recurrence rule equivalent to the schedule of the issue,
+// or false if it cannot be expressed as one (year parity or lists, holiday shifts,
+// business days with holidays, cron expressions restricting both day of month and day of week).
+func (i *Issue) RRule(calendar Calendar) (string, bool) {
+	if len(i.Years) > 0 || i.YearParity != nil {
+		return "", false
+	}
+	if i.OnHoliday != nil && calendar.hasHolidays() {
+		return "", false
+	}
+
+	var parts []string
+	switch {
+	case i.Schedule != nil:
+		schedule, err := ParseCron(*i.Schedule)
+		if err != nil || (schedule.dayOfMonthRestricted && schedule.dayOfWeekRestricted) {
+			return "", false
+		}
+		parts = append(parts, "FREQ=DAILY")
+		if months := bitsToList(schedule.month, 1, 12); len(months) < 12 {
+			parts = append(parts, "BYMONTH="+joinInts(months))
+		}
+		// Steps such as "*/2" are not "restricted" for matching but still limit days
+		if days := bitsToList(schedule.dayOfMonth, 1, 31); len(days) < 31 {
+			parts = append(parts, "BYMONTHDAY="+joinInts(days))
+		}
+		if weekdays := bitsToList(schedule.dayOfWeek, 0, 6); len(weekdays) < 7 {
+			var days []string
+			for _, weekday := range weekdays {
+				days = append(days, icalWeekdays[time.Weekday(weekday)])
+			}
+			parts = append(parts, "BYDAY="+strings.Join(days, ","))
+		}
+	default:
+		parts = append(parts, "FREQ=MONTHLY")
+		switch {
+		case i.Every != nil:
+			parts = append(parts, fmt.Sprintf("INTERVAL=%d", i.Every.Months))
+		case len(i.CreationMonths) > 0:
+			months := make([]int, 0, len(i.CreationMonths))
+			for _, month := range i.CreationMonths {
+				months = append(months, int(month))
+			}
+			sort.Ints(months)
+			parts = append(parts, "BYMONTH="+joinInts(months))
+		}
+
+		if i.Day == nil {
+			// Month-level schedules fall on the first day of the month
+			parts = append(parts, "BYMONTHDAY=1")
+			break
+		}
+		day, err := ParseDaySpec(*i.Day)
+		if err != nil {
+			return "", false
+		}
+		switch day.unit {
+		case dayUnitDay:
+			parts = append(parts, fmt.Sprintf("BYMONTHDAY=%d", day.Ordinal))
+		case dayUnitWeekday:
+			parts = append(parts, fmt.Sprintf("BYDAY=%d%s", day.Ordinal, icalWeekdays[day.weekday]))
+		case dayUnitBusinessDay:
+			if calendar.hasHolidays() {
+				return "", false
+			}
+			parts = append(parts, "BYDAY=MO,TU,WE,TH,FR", fmt.Sprintf("BYSETPOS=%d", day.Ordinal))
+		}
+	}
+
+	if i.ActiveUntil != nil {
+		until, err := ParseDate(*i.ActiveUntil)
+		if err != nil {
+			return "", false
+		}
+		parts = append(parts, "UNTIL="+until.Format("20060102"))
+	}
+
+	return strings.Join(parts, ";"), true
+}
+
+var icalWeekdays = map[time.Weekday]string{
+	time.Sunday:    "SU",
+	time.Monday:    "MO",
+	time.Tuesday:   "TU",
+	time.Wednesday: "WE",
+	time.Thursday:  "TH",
+	time.Friday:    "FR",
+	time.Saturday:  "SA",
+}
+
+// titleIsDateIndependent reports whether the title renders the same on any date,
+// in which case a single recurring event can carry it.
+func titleIsDateIndependent(issue IssueToCreate) (bool, error) {
+	first, second := issue, issue
+	first.Date = time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC)
+	second.Date = time.Date(2004, time.May, 6, 0, 0, 0, 0, time.UTC)
+
+	firstTitle, err := buildTitle(first)
+	if err != nil {
+		return false, err
+	}
+	secondTitle, err := buildTitle(second)
+	if err != nil {
+		return false, err
+	}
+	return firstTitle == secondTitle, nil
+}
+
+func icalUID(name string, occurrence string) string {
+	sum := sha1.Sum([]byte(name + "\x00" + occurrence))
+	return fmt.Sprintf("%x@recurring-backlog-item-creator", sum[:10])
+}
+
+func bitsToList(bits uint64, min int, max int) []int {
+	var values []int
+	for v := min; v <= max; v++ {
+		if bits&(1<<uint(v)) != 0 {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func joinInts(values []int) string {
+	strs := make([]string, 0, len(values))
+	for _, v := range values {
+		strs = append(strs, strconv.Itoa(v))
+	}
+	return strings.Join(strs, ",")
+}
+
+// escapeICalText escapes a TEXT value as defined by RFC 5545.
+func escapeICalText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// foldICalLine splits lines longer than 75 octets as defined by RFC 5545,
+// without breaking UTF-8 sequences.
+func foldICalLine(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIssue_RRule(t *testing.T) {
+	withHolidays := NewCalendar(Defaults{Holidays: Holidays{Dates: []string{"2025-01-01"}}})
+
+	cases := []struct {
+		name     string
+		issue    Issue
+		calendar Calendar
+		expect   string
+		expectOk bool
+	}{
+		{
+			name:     "creation months",
+			issue:    Issue{CreationMonths: []Month{July, January, March}},
+			expect:   "FREQ=MONTHLY;BYMONTH=1,3,7;BYMONTHDAY=1",
+			expectOk: true,
+		},
+		{
+			name:     "every with nth weekday",
+			issue:    Issue{Every: &Interval{Months: 2, Starting: "2025-02"}, Day: stringPtr("2nd-tuesday")},
+			expect:   "FREQ=MONTHLY;INTERVAL=2;BYDAY=2TU",
+			expectOk: true,
+		},
+		{
+			name:     "last business day of quarter",
+			issue:    Issue{CreationMonths: []Month{March, June, September, December}, Day: stringPtr("last-business-day")},
+			expect:   "FREQ=MONTHLY;BYMONTH=3,6,9,12;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+			expectOk: true,
+		},
+		{
+			name:     "day alone",
+			issue:    Issue{Day: stringPtr("last-day")},
+			expect:   "FREQ=MONTHLY;BYMONTHDAY=-1",
+			expectOk: true,
+		},
+		{
+			name:     "weekly cron",
+			issue:    Issue{Schedule: stringPtr("0 9 * * MON")},
+			expect:   "FREQ=DAILY;BYDAY=MO",
+			expectOk: true,
+		},
+		{
+			name:     "cron with months and days",
+			issue:    Issue{Schedule: stringPtr("0 9 1,15 JAN-MAR *")},
+			expect:   "FREQ=DAILY;BYMONTH=1,2,3;BYMONTHDAY=1,15",
+			expectOk: true,
+		},
+		{
+			name:     "active_until",
+			issue:    Issue{CreationMonths: []Month{January}, ActiveUntil: stringPtr("2027-12-31")},
+			expect:   "FREQ=MONTHLY;BYMONTH=1;BYMONTHDAY=1;UNTIL=20271231",
+			expectOk: true,
+		},
+		{
+			name:     "cron with either day of month or weekday",
+			issue:    Issue{Schedule: stringPtr("0 9 1 * FRI")},
+			expectOk: false,
+		},
+		{
+			name:     "year parity",
+			issue:    Issue{CreationMonths: []Month{April}, YearParity: stringPtr("odd")},
+			expectOk: false,
+		},
+		{
+			name:     "business days with holidays",
+			issue:    Issue{Day: stringPtr("first-business-day")},
+			calendar: withHolidays,
+			expectOk: false,
+		},
+		{
+			name:     "holiday shift with holidays",
+			issue:    Issue{Schedule: stringPtr("0 9 * * MON"), OnHoliday: stringPtr(OnHolidayNext)},
+			calendar: withHolidays,
+			expectOk: false,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.issue.RRule(tt.calendar)
+			if ok != tt.expectOk {
+				t.Fatalf("expected ok=%v, got %v (%q)", tt.expectOk, ok, got)
+			}
+			if ok && got != tt.expect {
+				t.Errorf("expected %q, got %q", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestExportICal(t *testing.T) {
+	config := Config{
+		Defaults: Defaults{ProjectID: "default_project_id", TargetRepo: "default/repo"},
+		Issues: []Issue{
+			{
+				Name:         "Weekly Ops Review",
+				Schedule:     stringPtr("0 9 * * MON"),
+				TemplateFile: stringPtr(".github/ISSUE_TEMPLATE/ops.md"),
+			},
+			{
+				Name:           "Quarterly Planning",
+				CreationMonths: []Month{March, June},
+				TitleSuffix:    stringPtr("- {{YearMonth}}"),
+				TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/planning.md"),
+			},
+		},
+	}
+	start := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	stamp := time.Date(2025, time.February, 20, 12, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	if err := ExportICal(&buf, config, start, 6, stamp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"DTSTAMP:20250220T120000Z\r\n",
+		// Date-independent title: one recurring event from the first Monday
+		"DTSTART;VALUE=DATE:20250303\r\nSUMMARY:Weekly Ops Review\r\nDESCRIPTION:.github/ISSUE_TEMPLATE/ops.md\r\nRRULE:FREQ=DAILY;BYDAY=MO\r\n",
+		// Templated title: one event per occurrence
+		"DTSTART;VALUE=DATE:20250301\r\nSUMMARY:Quarterly Planning - 2025-03\r\n",
+		"DTSTART;VALUE=DATE:20250601\r\nSUMMARY:Quarterly Planning - 2025-06\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, got)
+		}
+	}
+	if n := strings.Count(got, "BEGIN:VEVENT"); n != 3 {
+		t.Errorf("expected 3 events, got %d", n)
+	}
+}
+
+func TestExportICal_StartsMidMonth(t *testing.T) {
+	config := Config{
+		Defaults: Defaults{ProjectID: "default_project_id", TargetRepo: "default/repo"},
+		Issues: []Issue{
+			{Name: "Quarterly Planning", CreationMonths: []Month{March, June}},
+		},
+	}
+	start := time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	if err := ExportICal(&buf, config, start, 6, start); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{"DTSTART;VALUE=DATE:20250315\r\n", "DTSTART;VALUE=DATE:20250601\r\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestEscapeICalText(t *testing.T) {
+	got := escapeICalText("a,b;c\\d\ne")
+	expected := `a\,b\;c\\d\ne`
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestFoldICalLine(t *testing.T) {
+	line := "SUMMARY:" + strings.Repeat("あ", 40)
+	folded := foldICalLine(line)
+	for _, part := range strings.Split(folded, "\r\n") {
+		if len(part) > 75 {
+			t.Errorf("folded line exceeds 75 octets: %d", len(part))
+		}
+	}
+	if unfolded := strings.ReplaceAll(folded, "\r\n ", ""); unfolded != line {
+		t.Errorf("unfolding does not restore the line: %q", unfolded)
+	}
+}
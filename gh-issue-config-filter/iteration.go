@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProjectFieldIteration is an iteration of an ITERATION field, completed or not.
+type ProjectFieldIteration struct {
+	ID    string
+	Title string
+	// StartDate is YYYY-MM-DD
+	StartDate string
+	// Duration is in days
+	Duration int
+}
+
+func (it ProjectFieldIteration) start() (time.Time, error) {
+	return time.Parse(dateFieldLayout, it.StartDate)
+}
+
+// Relative iteration values
+const (
+	IterationCurrent = "current"
+	IterationNext    = "next"
+)
+
+var iterationOffsetPattern = regexp.MustCompile(`^@([+-]\d+)$`)
+
+// iterationOffset parses current, next and @+N into an offset from the current iteration.
+func iterationOffset(value string) (int, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case IterationCurrent:
+		return 0, true
+	case IterationNext:
+		return 1, true
+	}
+	matches := iterationOffsetPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if matches == nil {
+		return 0, false
+	}
+	offset, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, false
+	}
+	return offset, true
+}
+
+// ValidateIterationValue checks an ITERATION field value without an occurrence date:
+// current, next and @+N are always accepted, other values must be the title of an iteration.
+func ValidateIterationValue(field ProjectField, value string) error {
+	if _, ok := iterationOffset(value); ok {
+		return nil
+	}
+	for _, iteration := range field.Iterations {
+		if iteration.Title == value {
+			return nil
+		}
+	}
+	titles := make([]string, 0, len(field.Iterations))
+	for _, iteration := range field.Iterations {
+		titles = append(titles, iteration.Title)
+	}
+	return fmt.Errorf("iteration '%s' does not exist (must be current, next, @+N or one of %v)", value, titles)
+}
+
+// ResolveIteration returns the iteration of field that value refers to for an occurrence on date.
+// current is the iteration date falls in, next the one after it, and @+N the Nth one after it
+// (@-N before it). When date falls in a break between iterations, next and @+1 are the first
+// iteration after the break, and current does not exist. Other values are iteration titles.
+func ResolveIteration(field ProjectField, value string, date time.Time) (ProjectFieldIteration, error) {
+	offset, relative := iterationOffset(value)
+	if !relative {
+		for _, iteration := range field.Iterations {
+			if iteration.Title == value {
+				return iteration, nil
+			}
+		}
+		return ProjectFieldIteration{}, ValidateIterationValue(field, value)
+	}
+
+	iterations := make([]ProjectFieldIteration, len(field.Iterations))
+	copy(iterations, field.Iterations)
+	sort.Slice(iterations, func(i, j int) bool { return iterations[i].StartDate < iterations[j].StartDate })
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	// Index of the current iteration, or of the first one after date when date is in a break
+	index := len(iterations)
+	inIteration := false
+	for i, iteration := range iterations {
+		start, err := iteration.start()
+		if err != nil {
+			return ProjectFieldIteration{}, fmt.Errorf("iteration '%s' has an invalid start date '%s'", iteration.Title, iteration.StartDate)
+		}
+		if day.Before(start) {
+			index = i
+			break
+		}
+		if day.Before(start.AddDate(0, 0, iteration.Duration)) {
+			index = i
+			inIteration = true
+			break
+		}
+	}
+	if !inIteration {
+		if offset == 0 {
+			return ProjectFieldIteration{}, fmt.Errorf("no iteration of field '%s' is current on %s", field.Name, day.Format(dateFieldLayout))
+		}
+		if offset > 0 {
+			// The first iteration after the break is already one ahead
+			offset--
+		}
+	}
+
+	target := index + offset
+	if target < 0 || target >= len(iterations) {
+		return ProjectFieldIteration{}, fmt.Errorf("field '%s' has no iteration '%s' relative to %s", field.Name, value, day.Format(dateFieldLayout))
+	}
+	return iterations[target], nil
+}
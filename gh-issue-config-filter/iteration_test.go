@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolveIteration(t *testing.T) {
+	// Two-week sprints with a one-week break after Sprint 2, listed out of order
+	field := ProjectField{
+		ID:       "PVTIF_1",
+		Name:     "Sprint",
+		DataType: "ITERATION",
+		Iterations: []ProjectFieldIteration{
+			{ID: "IT_3", Title: "Sprint 3", StartDate: "2025-03-10", Duration: 14},
+			{ID: "IT_1", Title: "Sprint 1", StartDate: "2025-02-03", Duration: 14},
+			{ID: "IT_2", Title: "Sprint 2", StartDate: "2025-02-17", Duration: 14},
+			{ID: "IT_4", Title: "Sprint 4", StartDate: "2025-03-24", Duration: 14},
+		},
+	}
+	inSprint2 := time.Date(2025, time.February, 20, 9, 0, 0, 0, time.UTC)
+	inBreak := time.Date(2025, time.March, 5, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		value        string
+		date         time.Time
+		expectedID   string
+		errorPattern string
+	}{
+		{name: "current", value: "current", date: inSprint2, expectedID: "IT_2"},
+		{name: "current on the first day", value: "current", date: time.Date(2025, time.February, 17, 0, 0, 0, 0, time.UTC), expectedID: "IT_2"},
+		{name: "current on the last day", value: "current", date: time.Date(2025, time.March, 2, 23, 0, 0, 0, time.UTC), expectedID: "IT_2"},
+		{name: "next", value: "next", date: inSprint2, expectedID: "IT_3"},
+		{name: "offset", value: "@+2", date: inSprint2, expectedID: "IT_4"},
+		{name: "negative offset", value: "@-1", date: inSprint2, expectedID: "IT_1"},
+		{name: "case insensitive", value: "Next", date: inSprint2, expectedID: "IT_3"},
+		{name: "title", value: "Sprint 1", date: inSprint2, expectedID: "IT_1"},
+		{name: "next in a break", value: "next", date: inBreak, expectedID: "IT_3"},
+		{name: "previous in a break", value: "@-1", date: inBreak, expectedID: "IT_2"},
+		{name: "current in a break", value: "current", date: inBreak, errorPattern: "no iteration of field 'Sprint' is current on 2025-03-05"},
+		{name: "beyond the last iteration", value: "@+3", date: inSprint2, errorPattern: "has no iteration '@+3'"},
+		{name: "unknown title", value: "Sprint 9", date: inSprint2, errorPattern: "iteration 'Sprint 9' does not exist"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iteration, err := ResolveIteration(field, tt.value, tt.date)
+			if tt.errorPattern != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorPattern) {
+					t.Errorf("expected error containing %q, got %v (iteration %+v)", tt.errorPattern, err, iteration)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if iteration.ID != tt.expectedID {
+				t.Errorf("expected %s, got %s (%s)", tt.expectedID, iteration.ID, iteration.Title)
+			}
+		})
+	}
+}
+
+func TestValidateIterationValue(t *testing.T) {
+	field := ProjectField{Name: "Sprint", DataType: "ITERATION", Iterations: []ProjectFieldIteration{{ID: "IT_1", Title: "Sprint 1"}}}
+	for _, value := range []string{"current", "next", "@+2", "@-1", "Sprint 1"} {
+		if err := ValidateIterationValue(field, value); err != nil {
+			t.Errorf("%q: unexpected error: %v", value, err)
+		}
+	}
+	for _, value := range []string{"Sprint 2", "@2", "later"} {
+		if err := ValidateIterationValue(field, value); err == nil {
+			t.Errorf("%q: expected error, got nil", value)
+		}
+	}
+}
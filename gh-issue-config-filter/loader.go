@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -23,6 +24,23 @@ func ParseRepo(repoStr string) (Repo, error) {
 	}, nil
 }
 
+func ParseYearMonth(yearMonthStr string) (YearMonth, error) {
+	t, err := time.Parse("2006-01", yearMonthStr)
+	if err != nil {
+		return YearMonth{}, fmt.Errorf("invalid year-month format: %s (expected 'YYYY-MM')", yearMonthStr)
+	}
+	return YearMonthOf(t), nil
+}
+
+// ParseDate parses a calendar date. The result is midnight UTC so that dates compare by day.
+func ParseDate(dateStr string) (time.Time, error) {
+	t, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date format: %s (expected 'YYYY-MM-DD')", dateStr)
+	}
+	return t, nil
+}
+
 func LoadConfig(configFile string) (Config, error) {
 	Debug("loading config file: ", configFile)
 
@@ -46,6 +64,14 @@ func LoadConfig(configFile string) (Config, error) {
 		return config, err
 	}
 
+	if config.Defaults.Holidays.ICalFile != nil {
+		dates, err := loadHolidayFile(*config.Defaults.Holidays.ICalFile)
+		if err != nil {
+			return config, fmt.Errorf("failed to load holidays: %w", err)
+		}
+		config.Defaults.Holidays.Dates = append(config.Defaults.Holidays.Dates, dates...)
+	}
+
 	Debug("loaded config file: ", &config)
 
 	return config, nil
@@ -86,3 +112,76 @@ func ParseMonth(digit int) (Month, error) {
 	}
 	return month, nil
 }
+
+// ParseTimestamp parses either a date (YYYY-MM-DD, midnight of that day in the timezone of
+// each issue) or an RFC3339 timestamp.
+func ParseTimestamp(value string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, calendarDay); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q (expected YYYY-MM-DD or RFC3339)", value)
+	}
+	return t, nil
+}
+
+// LoadLastRun reads the timestamp of the last successful run from path.
+// It returns false if the file does not exist yet.
+func LoadLastRun(path string) (time.Time, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return time.Time{}, false, nil
+		}
+		return time.Time{}, false, err
+	}
+	t, err := ParseTimestamp(strings.TrimSpace(string(data)))
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid last run file %s: %w", path, err)
+	}
+	return t, true, nil
+}
+
+// ResolveNow determines the date issues are filtered and rendered against.
+// At most one of month (1-12, 0 for unset), date (YYYY-MM-DD) or now (RFC3339)
+// may be set; when none is set, clock is returned unchanged. Dates without a time are
+// calendarDay dates, the same day in the timezone of every issue.
+func ResolveNow(month int, date string, now string, clock time.Time) (time.Time, error) {
+	set := 0
+	for _, isSet := range []bool{month != 0, date != "", now != ""} {
+		if isSet {
+			set++
+		}
+	}
+	if set > 1 {
+		return time.Time{}, errors.New("only one of --month, --date or --now may be specified")
+	}
+
+	switch {
+	case month != 0:
+		monthEnum, err := ParseMonth(month)
+		if err != nil {
+			return time.Time{}, err
+		}
+		t := DateInMonth(monthEnum, clock)
+		if !t.Equal(clock) {
+			// The first day of another month is that day whatever the timezone of the issue
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, calendarDay)
+		}
+		return t, nil
+	case date != "":
+		t, err := time.ParseInLocation("2006-01-02", date, calendarDay)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q (expected YYYY-MM-DD): %w", date, err)
+		}
+		return t, nil
+	case now != "":
+		t, err := time.Parse(time.RFC3339, now)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp %q (expected RFC3339): %w", now, err)
+		}
+		return t, nil
+	}
+	return clock, nil
+}
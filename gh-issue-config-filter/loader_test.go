@@ -1,7 +1,10 @@
 package main
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -10,3 +13,130 @@ func TestLoadConfig(t *testing.T) {
 		t.Errorf("failed to load config: %v", err)
 	}
 }
+
+func TestResolveNow(t *testing.T) {
+	clock := time.Date(2025, time.March, 20, 10, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name        string
+		month       int
+		date        string
+		now         string
+		expect      time.Time
+		expectError bool
+	}{
+		{
+			name:   "defaults to clock",
+			expect: clock,
+		},
+		{
+			name:   "current month keeps clock",
+			month:  3,
+			expect: clock,
+		},
+		{
+			name:   "other month uses first day",
+			month:  7,
+			expect: time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "date",
+			date:   "2024-12-31",
+			expect: time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "now",
+			now:    "2024-12-31T23:59:00+09:00",
+			expect: time.Date(2024, time.December, 31, 14, 59, 0, 0, time.UTC),
+		},
+		{
+			name:        "invalid month",
+			month:       13,
+			expectError: true,
+		},
+		{
+			name:        "invalid date",
+			date:        "2024/12/31",
+			expectError: true,
+		},
+		{
+			name:        "invalid now",
+			now:         "2024-12-31",
+			expectError: true,
+		},
+		{
+			name:        "multiple inputs",
+			month:       3,
+			date:        "2024-12-31",
+			expectError: true,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveNow(tt.month, tt.date, tt.now, clock)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.expect) {
+				t.Errorf("expected %v, got %v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	// A date is the same calendar day in the timezone of every issue
+	got, err := ParseTimestamp("2025-03-01")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Location() != calendarDay || got.Format("2006-01-02 15:04") != "2025-03-01 00:00" {
+		t.Errorf("expected the calendar day 2025-03-01, got %v", got)
+	}
+
+	got, err = ParseTimestamp("2025-03-01T09:00:00Z")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC); !got.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	if _, err := ParseTimestamp("last week"); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestLoadLastRun(t *testing.T) {
+	dir := t.TempDir()
+
+	_, found, err := LoadLastRun(filepath.Join(dir, "missing"))
+	if err != nil || found {
+		t.Errorf("expected missing file to be reported as not found, got found=%v err=%v", found, err)
+	}
+
+	path := filepath.Join(dir, "last-run")
+	if err := os.WriteFile(path, []byte("2025-03-01T09:00:00Z\n"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	got, found, err := LoadLastRun(path)
+	if err != nil || !found {
+		t.Fatalf("expected last run, got found=%v err=%v", found, err)
+	}
+	if expected := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC); !got.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	if err := os.WriteFile(path, []byte("garbage"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if _, _, err := LoadLastRun(path); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
@@ -5,53 +5,366 @@ import (
 	"flag"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	_ "time/tzdata" // timezones must resolve on runners without zoneinfo
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "forecast":
+			runForecast(os.Args[2:])
+			return
+		case "export":
+			runExport(os.Args[2:])
+			return
+		case "apply":
+			runApply(os.Args[2:])
+			return
+		}
+	}
+	runFilter(os.Args[1:])
+}
+
+// runFilter prints the issues to create as JSON.
+func runFilter(args []string) {
+	flags := flag.NewFlagSet("gh-issue-config-filter", flag.ExitOnError)
 	var (
-		month      = flag.Int("month", 0, "Month (1-12) to filter issues")
-		configFile = flag.String("config", "", "Path to config file (required)")
-		debug      = flag.Bool("debug", false, "Enable debug logging")
+		month       = flags.Int("month", 0, "Month (1-12) to filter issues (deprecated: use --date)")
+		date        = flags.String("date", "", "Date (YYYY-MM-DD) to filter issues and render titles with (default: today)")
+		nowFlag     = flags.String("now", "", "Timestamp (RFC3339) to filter issues and render titles with (default: current time)")
+		since       = flags.String("since", "", "Catch up every occurrence after this date (YYYY-MM-DD) or timestamp (RFC3339)")
+		catchUp     = flags.String("catch-up", "", "Path to a file holding the timestamp of the last successful run to catch up from")
+		existing    = flags.String("existing", ExistingSkip, "What to do with issues already created for their period: skip, mark or ignore")
+		planOut     = flags.String("plan-out", "", "Path to write a plan file for apply --plan to")
+		configFile  = flags.String("config", "", "Path to config file (required)")
+		concurrency = flags.Int("concurrency", 1, "Number of issues validated at a time")
+		cacheDir    = flags.String("cache-dir", "", "Directory to cache project names and fields in between runs (default: no disk cache)")
+		cacheTTL    = flags.Duration("cache-ttl", time.Hour, "How long project metadata cached in --cache-dir is used")
+		debug       = flags.Bool("debug", false, "Enable debug logging")
 	)
-	flag.Parse()
+	retry := addRetryFlags(flags)
+	_ = flags.Parse(args)
 
 	SetDebugMode(*debug)
 
-	monthEnum, err := ParseMonth(*month)
+	if err := ValidateExistingPolicy(*existing); err != nil {
+		log.Fatalf("invalid --existing: %v", err)
+	}
+	if *concurrency < 1 {
+		log.Fatalf("--concurrency must be at least 1")
+	}
+
+	config := loadConfig(*configFile)
+	clock := configClock(config)
+
+	now, err := ResolveNow(*month, *date, *nowFlag, clock)
 	if err != nil {
-		log.Fatalf("failed to parse month: %v", err)
+		log.Fatalf("failed to resolve date: %v", err)
 	}
 
-	configPath := *configFile
-	if configPath == "" {
-		log.Fatalf("config file is required. Use --config to specify a config file")
+	if *since != "" && *catchUp != "" {
+		log.Fatalf("only one of --since or --catch-up may be specified")
+	}
+	var sinceTime time.Time
+	if *since != "" {
+		if sinceTime, err = ParseTimestamp(*since); err != nil {
+			log.Fatalf("failed to parse --since: %v", err)
+		}
+	}
+	if *catchUp != "" {
+		lastRun, found, err := LoadLastRun(*catchUp)
+		if err != nil {
+			log.Fatalf("failed to load last run: %v", err)
+		}
+		if found {
+			sinceTime = lastRun
+		} else {
+			log.Printf("No last run recorded in %s, nothing to catch up", *catchUp)
+		}
+	}
+	if !sinceTime.IsZero() && !sinceTime.Before(now) {
+		log.Fatalf("catch-up start %s must be before %s", sinceTime.Format(time.RFC3339), now.Format(time.RFC3339))
 	}
 
-	config, err := LoadConfig(configPath)
+	// Create GitHub client for validation, fetching the metadata of each project once
+	githubClient, err := NewGitHubClient(*retry)
 	if err != nil {
-		if os.IsNotExist(err) {
-			log.Fatalf("config file not found: %s\nUse --config to specify a different config file", configPath)
+		log.Fatalf("failed to create GitHub client: %v", err)
+	}
+	ghClient := NewCachingClient(githubClient, *cacheDir, *cacheTTL)
+
+	// Cancelling stops issues not validated or applied yet from being started
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := ValidateConfigConcurrently(ctx, config, ghClient, *concurrency); err != nil {
+		log.Fatalf("config validation failed: %v", err)
+	}
+
+	var issuesToCreate IssuesToCreate
+	if sinceTime.IsZero() {
+		// Display the date issues are filtered for
+		log.Printf("Looking for issues to be created on %s", now.Format("2006-01-02"))
+		issuesToCreate = GetIssuesToCreate(config, now)
+	} else {
+		log.Printf("Catching up issues to be created after %s until %s", sinceTime.Format(time.RFC3339), now.Format("2006-01-02"))
+		issuesToCreate = GetIssuesToCreateSince(config, sinceTime, now)
+	}
+
+	outputs, err := outputJSON(ctx, issuesToCreate, config.Defaults, ghClient, *existing)
+	if err != nil {
+		log.Fatalf("failed to output JSON: %v", err)
+	}
+
+	if *planOut != "" {
+		plan, err := NewPlan(ctx, *configFile, outputs, ghClient, time.Now(), planSigningKey())
+		if err != nil {
+			log.Fatalf("failed to make plan: %v", err)
 		}
-		log.Fatalf("failed to load config: %v", err)
+		if err := WritePlan(*planOut, plan); err != nil {
+			log.Fatalf("failed to write plan: %v", err)
+		}
+		log.Printf("Wrote plan for %d issue(s) to %s", len(plan.Issues), *planOut)
 	}
+}
+
+// runForecast prints the issues that will be created over the coming months.
+// It does not call the GitHub API.
+func runForecast(args []string) {
+	flags := flag.NewFlagSet("gh-issue-config-filter forecast", flag.ExitOnError)
+	var (
+		months     = flags.Int("months", 12, "Number of months to forecast")
+		date       = flags.String("date", "", "First day (YYYY-MM-DD) of the forecast (default: today)")
+		nowFlag    = flags.String("now", "", "Timestamp (RFC3339) to start the forecast at (default: current time)")
+		configFile = flags.String("config", "", "Path to config file (required)")
+		debug      = flags.Bool("debug", false, "Enable debug logging")
+	)
+	_ = flags.Parse(args)
 
-	// Create GitHub client for validation
-	ghClient, err := NewGitHubClient()
+	SetDebugMode(*debug)
+
+	if *months <= 0 {
+		log.Fatalf("--months must be at least 1")
+	}
+
+	config := loadConfig(*configFile)
+	start, err := ResolveNow(0, *date, *nowFlag, configClock(config))
 	if err != nil {
-		log.Fatalf("failed to create GitHub client: %v", err)
+		log.Fatalf("failed to resolve date: %v", err)
 	}
 
-	if err := ValidateConfig(config, ghClient); err != nil {
+	if err := ValidateConfigOffline(config); err != nil {
 		log.Fatalf("config validation failed: %v", err)
 	}
 
-	// Display current month
-	log.Printf("Looking for issues to be created in %s", monthEnum)
+	entries, err := Forecast(config, start, *months)
+	if err != nil {
+		log.Fatalf("failed to forecast: %v", err)
+	}
+	if err := WriteForecast(os.Stdout, entries, start, *months); err != nil {
+		log.Fatalf("failed to write forecast: %v", err)
+	}
+}
+
+// runExport writes the schedule in another format. Only "ical" is supported.
+func runExport(args []string) {
+	if len(args) == 0 || args[0] != "ical" {
+		log.Fatalf("usage: gh-issue-config-filter export ical --config <config-file> [--output <file.ics>]")
+	}
 
-	issuesToCreate := GetIssuesToCreate(config, monthEnum)
+	flags := flag.NewFlagSet("gh-issue-config-filter export ical", flag.ExitOnError)
+	var (
+		output     = flags.String("output", "", "Path to write the .ics file to (default: stdout)")
+		months     = flags.Int("months", 12, "Number of months to expand schedules that cannot be expressed as recurrence rules")
+		date       = flags.String("date", "", "First day (YYYY-MM-DD) of the exported schedule (default: today)")
+		nowFlag    = flags.String("now", "", "Timestamp (RFC3339) to start the exported schedule at (default: current time)")
+		configFile = flags.String("config", "", "Path to config file (required)")
+		debug      = flags.Bool("debug", false, "Enable debug logging")
+	)
+	_ = flags.Parse(args[1:])
 
-	ctx := context.Background()
-	if err := outputJSON(ctx, issuesToCreate, config.Defaults, ghClient); err != nil {
-		log.Fatalf("failed to output JSON: %v", err)
+	SetDebugMode(*debug)
+
+	if *months <= 0 {
+		log.Fatalf("--months must be at least 1")
+	}
+
+	config := loadConfig(*configFile)
+	clock := configClock(config)
+	start, err := ResolveNow(0, *date, *nowFlag, clock)
+	if err != nil {
+		log.Fatalf("failed to resolve date: %v", err)
+	}
+
+	if err := ValidateConfigOffline(config); err != nil {
+		log.Fatalf("config validation failed: %v", err)
+	}
+
+	w := os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("failed to create %s: %v", *output, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := ExportICal(w, config, start, *months, clock); err != nil {
+		log.Fatalf("failed to export iCalendar: %v", err)
+	}
+	if *output != "" {
+		log.Printf("Wrote %s", *output)
+	}
+}
+
+// runApply creates the issues printed by the filter, or recorded in a plan, and registers
+// them to their projects.
+func runApply(args []string) {
+	flags := flag.NewFlagSet("gh-issue-config-filter apply", flag.ExitOnError)
+	var (
+		input       = flags.String("input", "-", "Path to the JSON printed by the filter (default: stdin)")
+		planFile    = flags.String("plan", "", "Path to a plan file written with --plan-out, applied only if nothing changed since")
+		configFile  = flags.String("config", "", "Path to config file to check the plan against (default: the one recorded in the plan)")
+		interval    = flags.Duration("interval", time.Second, "Time to wait between starting issues, shared by all of them")
+		concurrency = flags.Int("concurrency", 1, "Number of issues created at a time")
+		onFailure   = flags.String("on-failure", OnFailureRollback, "What to do with an issue that fails part-way: rollback, continue or resume")
+		rollback    = flags.String("rollback-issues", RollbackClose, "How rollback undoes a created issue: close or delete (needs admin access)")
+		stateFile   = flags.String("state-file", "", "Path to the state file recording the steps taken for each issue (required for --on-failure resume)")
+		dryRun      = flags.Bool("dry-run", false, "Print the API calls that would be sent instead of sending them")
+		format      = flags.String("format", "text", "Output format of --dry-run: text or json")
+		debug       = flags.Bool("debug", false, "Enable debug logging")
+	)
+	retry := addRetryFlags(flags)
+	_ = flags.Parse(args)
+
+	SetDebugMode(*debug)
+
+	if *format != "text" && *format != "json" {
+		log.Fatalf("invalid --format '%s' (must be 'text' or 'json')", *format)
+	}
+	if err := ValidateOnFailurePolicy(*onFailure); err != nil {
+		log.Fatalf("invalid --on-failure: %v", err)
+	}
+	if *rollback != RollbackClose && *rollback != RollbackDelete {
+		log.Fatalf("invalid --rollback-issues '%s' (must be 'close' or 'delete')", *rollback)
+	}
+	if *onFailure == OnFailureResume && *stateFile == "" {
+		log.Fatalf("--on-failure resume requires --state-file")
+	}
+	if *concurrency < 1 {
+		log.Fatalf("--concurrency must be at least 1")
+	}
+
+	ghClient, err := NewGitHubClient(*retry)
+	if err != nil {
+		log.Fatalf("failed to create GitHub client: %v", err)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var outputs []IssueOutput
+	if *planFile != "" {
+		plan, err := LoadPlan(*planFile)
+		if err != nil {
+			log.Fatalf("failed to load plan: %v", err)
+		}
+		if err := VerifyPlan(ctx, plan, *configFile, ghClient, planSigningKey()); err != nil {
+			log.Fatalf("refusing to apply plan: %v", err)
+		}
+		log.Printf("Plan %s verified (made at %s)", *planFile, plan.CreatedAt.Format(time.RFC3339))
+		outputs = plan.Issues
+	} else {
+		r := os.Stdin
+		if *input != "-" {
+			f, err := os.Open(*input)
+			if err != nil {
+				log.Fatalf("failed to open %s: %v", *input, err)
+			}
+			defer f.Close()
+			r = f
+		}
+		if outputs, err = LoadIssueOutputs(r); err != nil {
+			log.Fatalf("failed to load issues: %v", err)
+		}
+	}
+
+	if *dryRun {
+		if err := ValidateFieldUpdates(ctx, ghClient, outputs); err != nil {
+			log.Fatalf("field validation failed: %v", err)
+		}
+		dryRunClient := NewDryRunClient(ghClient)
+		results, err := ApplyIssues(ctx, dryRunClient, outputs, ApplyOptions{OnFailure: OnFailureContinue})
+		if err != nil {
+			log.Fatalf("failed to apply: %v", err)
+		}
+		if err := WriteDryRun(os.Stdout, dryRunClient.Calls, *format); err != nil {
+			log.Fatalf("failed to write dry run: %v", err)
+		}
+		log.Printf("Dry run: %d issue(s) would be created with %d API call(s)", len(results), len(dryRunClient.Calls))
+		return
+	}
+
+	txLog, err := LoadTransactionLog(*stateFile)
+	if err != nil {
+		log.Fatalf("failed to load state: %v", err)
+	}
+	results, err := ApplyIssues(ctx, ghClient, outputs, ApplyOptions{
+		Interval:       *interval,
+		Concurrency:    *concurrency,
+		OnFailure:      *onFailure,
+		RollbackIssues: *rollback,
+		Log:            txLog,
+	})
+	log.Printf("Created %d issue(s)", len(results))
+	if err != nil {
+		log.Fatalf("failed to apply: %v", err)
+	}
+}
+
+// addRetryFlags registers the flags configuring how GitHub requests are retried.
+func addRetryFlags(flags *flag.FlagSet) *RetryOptions {
+	retry := DefaultRetryOptions()
+	flags.IntVar(&retry.MaxAttempts, "max-attempts", retry.MaxAttempts, "Times a GitHub request is sent at most when rate limited or failing transiently")
+	flags.DurationVar(&retry.MaxWait, "max-retry-wait", retry.MaxWait, "Longest rate limit to wait out before giving up")
+	flags.Float64Var(&retry.Jitter, "retry-jitter", retry.Jitter, "Fraction (0-1) of each retry delay added at random")
+	return &retry
+}
+
+// planSigningKey returns the key plans are signed and verified with, if any.
+func planSigningKey() []byte {
+	return []byte(os.Getenv("PLAN_SIGNING_KEY"))
+}
+
+// loadConfig loads the config file, exiting on failure.
+func loadConfig(configPath string) Config {
+	if configPath == "" {
+		log.Fatalf("config file is required. Use --config to specify a config file")
+	}
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Fatalf("config file not found: %s\nUse --config to specify a different config file", configPath)
+		}
+		log.Fatalf("failed to load config: %v", err)
+	}
+	return config
+}
+
+// configClock returns the current time in defaults.timezone,
+// in which --date and --month are interpreted.
+func configClock(config Config) time.Time {
+	clock := time.Now()
+	loc, err := config.Defaults.GetLocation()
+	if err != nil {
+		log.Fatalf("invalid defaults.timezone: %v", err)
+	}
+	if loc != nil {
+		clock = clock.In(loc)
 	}
+	return clock
 }
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// How field and option names in the config are matched against the project
+const (
+	MatchExact           = "exact"
+	MatchCaseInsensitive = "case_insensitive"
+)
+
+// GetMatch returns the name matching mode, exact unless configured otherwise.
+func (d Defaults) GetMatch() string {
+	if d.Match == nil || *d.Match == "" {
+		return MatchExact
+	}
+	return *d.Match
+}
+
+// ValidateMatch checks defaults.match.
+func ValidateMatch(defaults Defaults) error {
+	switch defaults.GetMatch() {
+	case MatchExact, MatchCaseInsensitive:
+		return nil
+	default:
+		return fmt.Errorf("defaults.match: invalid value '%s' (must be '%s' or '%s')", *defaults.Match, MatchExact, MatchCaseInsensitive)
+	}
+}
+
+// matchName finds name among candidates: exactly, or ignoring case with MatchCaseInsensitive.
+// An exact match wins over matches differing in case; several of those are ambiguous.
+func matchName(name string, candidates []string, match string) (string, error) {
+	var folded []string
+	for _, candidate := range candidates {
+		if candidate == name {
+			return candidate, nil
+		}
+		if match == MatchCaseInsensitive && strings.EqualFold(candidate, name) {
+			folded = append(folded, candidate)
+		}
+	}
+	switch len(folded) {
+	case 0:
+		return "", errNameNotFound
+	case 1:
+		return folded[0], nil
+	default:
+		sort.Strings(folded)
+		return "", fmt.Errorf("'%s' matches %s ignoring case; use the exact name", name, quoteNames(folded))
+	}
+}
+
+var errNameNotFound = errors.New("not found")
+
+// LookupField finds a project field by name according to match. The error names the project
+// and suggests the closest field names.
+func LookupField(fieldMap map[string]ProjectField, name string, match string, projectName string) (ProjectField, error) {
+	names := make([]string, 0, len(fieldMap))
+	for fieldName := range fieldMap {
+		names = append(names, fieldName)
+	}
+	sort.Strings(names)
+
+	found, err := matchName(name, names, match)
+	if err == errNameNotFound {
+		return ProjectField{}, fmt.Errorf("field '%s' does not exist in project '%s'%s",
+			name, projectName, notFoundHint(name, names, "fields"))
+	}
+	if err != nil {
+		return ProjectField{}, fmt.Errorf("field %w in project '%s'", err, projectName)
+	}
+	return fieldMap[found], nil
+}
+
+// LookupOption finds an option of a SINGLE_SELECT field by name according to match.
+// The error suggests the closest option names.
+func LookupOption(field ProjectField, name string, match string) (ProjectFieldOption, error) {
+	names := make([]string, 0, len(field.Options))
+	for _, option := range field.Options {
+		names = append(names, option.Name)
+	}
+
+	found, err := matchName(name, names, match)
+	if err == errNameNotFound {
+		sorted := append([]string(nil), names...)
+		sort.Strings(sorted)
+		return ProjectFieldOption{}, fmt.Errorf("field '%s': option '%s' does not exist%s",
+			field.Name, name, notFoundHint(name, sorted, "options"))
+	}
+	if err != nil {
+		return ProjectFieldOption{}, fmt.Errorf("field '%s': option %w", field.Name, err)
+	}
+	for _, option := range field.Options {
+		if option.Name == found {
+			return option, nil
+		}
+	}
+	return ProjectFieldOption{}, fmt.Errorf("field '%s': option '%s' does not exist", field.Name, name)
+}
+
+// notFoundHint ends a not-found error with a suggestion, if any, and the sorted candidates.
+func notFoundHint(name string, sortedCandidates []string, kind string) string {
+	if suggestion, ok := closestName(name, sortedCandidates); ok {
+		return fmt.Sprintf("; did you mean '%s'? Available %s: %s", suggestion, kind, quoteNames(sortedCandidates))
+	}
+	return fmt.Sprintf(". Available %s: %s", kind, quoteNames(sortedCandidates))
+}
+
+// closestName returns the candidate closest to name, if any is close enough to be a likely typo.
+func closestName(name string, candidates []string) (string, bool) {
+	best := ""
+	bestDistance := -1
+	for _, candidate := range candidates {
+		distance := editDistance(strings.ToLower(name), strings.ToLower(candidate))
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	// Allow about one typo per three characters, so short names only match near misses
+	if bestDistance < 0 || bestDistance > max(1, len([]rune(name))/3) {
+		return "", false
+	}
+	return best, true
+}
+
+// editDistance is the Levenshtein distance between a and b, counted in runes.
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+func quoteNames(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "'" + name + "'"
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
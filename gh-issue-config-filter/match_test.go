@@ -0,0 +1,129 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLookupField(t *testing.T) {
+	fieldMap := map[string]ProjectField{
+		"Status":   {ID: "PVTF_status", Name: "Status"},
+		"Priority": {ID: "PVTF_priority", Name: "Priority"},
+		"Estimate": {ID: "PVTF_estimate", Name: "Estimate"},
+		"SP":       {ID: "PVTF_sp", Name: "SP"},
+		"sp":       {ID: "PVTF_sp_lower", Name: "sp"},
+	}
+
+	tests := []struct {
+		name                string
+		field               string
+		match               string
+		expectID            string
+		expectErrorContains string
+	}{
+		{
+			name:     "exact",
+			field:    "Priority",
+			match:    MatchExact,
+			expectID: "PVTF_priority",
+		},
+		{
+			name:                "different case is not found by default",
+			field:               "priority",
+			match:               MatchExact,
+			expectErrorContains: "field 'priority' does not exist in project 'Roadmap'; did you mean 'Priority'? Available fields: ['Estimate', 'Priority', 'SP', 'Status', 'sp']",
+		},
+		{
+			name:     "case-insensitive",
+			field:    "PRIORITY",
+			match:    MatchCaseInsensitive,
+			expectID: "PVTF_priority",
+		},
+		{
+			name:     "exact match wins over case-insensitive ones",
+			field:    "sp",
+			match:    MatchCaseInsensitive,
+			expectID: "PVTF_sp_lower",
+		},
+		{
+			name:                "ambiguous case-insensitive match",
+			field:               "Sp",
+			match:               MatchCaseInsensitive,
+			expectErrorContains: "field 'Sp' matches ['SP', 'sp'] ignoring case; use the exact name in project 'Roadmap'",
+		},
+		{
+			name:                "typo",
+			field:               "Priorty",
+			match:               MatchCaseInsensitive,
+			expectErrorContains: "did you mean 'Priority'?",
+		},
+		{
+			name:                "nothing close enough to suggest",
+			field:               "Assignee",
+			match:               MatchExact,
+			expectErrorContains: "field 'Assignee' does not exist in project 'Roadmap'. Available fields:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, err := LookupField(fieldMap, tt.field, tt.match, "Roadmap")
+			if tt.expectErrorContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectErrorContains) {
+					t.Errorf("expected error containing %q, got %v", tt.expectErrorContains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if field.ID != tt.expectID {
+				t.Errorf("expected field %s, got %s", tt.expectID, field.ID)
+			}
+		})
+	}
+}
+
+func TestLookupOption(t *testing.T) {
+	field := ProjectField{
+		Name:     "Status",
+		DataType: "SINGLE_SELECT",
+		Options: []ProjectFieldOption{
+			{ID: "OPT_todo", Name: "Todo"},
+			{ID: "OPT_progress", Name: "In Progress"},
+			{ID: "OPT_done", Name: "Done"},
+		},
+	}
+
+	option, err := LookupOption(field, "in progress", MatchCaseInsensitive)
+	if err != nil || option.ID != "OPT_progress" {
+		t.Errorf("expected OPT_progress, got %+v (error %v)", option, err)
+	}
+
+	_, err = LookupOption(field, "in progress", MatchExact)
+	expected := "field 'Status': option 'in progress' does not exist; did you mean 'In Progress'? Available options: ['Done', 'In Progress', 'Todo']"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected %q, got %v", expected, err)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"Status", "Status", 0},
+		{"Statsu", "Status", 2},
+		{"Priorty", "Priority", 1},
+		{"kitten", "sitting", 3},
+		{"", "Done", 4},
+		{"優先度", "優先", 1},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.expected {
+			t.Errorf("editDistance(%q, %q) = %d, expected %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
@@ -5,17 +5,30 @@ import (
 	"fmt"
 	"reflect"
 	"slices"
+	"time"
 )
 
 type Defaults struct {
-	ProjectID  string `yaml:"project_id"`
-	TargetRepo string `yaml:"target_repo"` // Format: "owner/repo"
+	ProjectID       string           `yaml:"project_id"`
+	TargetRepo      string           `yaml:"target_repo"` // Format: "owner/repo"
+	Holidays        Holidays         `yaml:"holidays,omitempty"`
+	BlackoutPeriods []BlackoutPeriod `yaml:"blackout_periods,omitempty"`
+	Timezone        *string          `yaml:"timezone,omitempty"` // IANA name, e.g. "Asia/Tokyo"
+	Match           *string          `yaml:"match,omitempty"`    // "exact" (default) or "case_insensitive"
 }
 
 func (d Defaults) GetTargetRepo() (Repo, error) {
 	return ParseRepo(d.TargetRepo)
 }
 
+// GetLocation returns the default timezone, or nil if none is configured.
+func (d Defaults) GetLocation() (*time.Location, error) {
+	if d.Timezone == nil {
+		return nil, nil
+	}
+	return time.LoadLocation(*d.Timezone)
+}
+
 type Config struct {
 	Defaults Defaults `yaml:"defaults"`
 	Issues   []Issue  `yaml:"issues"`
@@ -64,6 +77,25 @@ func (m Month) IsValid() bool {
 	return m >= January && m <= December
 }
 
+// YearMonth identifies a calendar month in a specific year.
+type YearMonth struct {
+	Year  int
+	Month Month
+}
+
+func YearMonthOf(t time.Time) YearMonth {
+	return YearMonth{Year: t.Year(), Month: Month(t.Month())}
+}
+
+func (y YearMonth) String() string {
+	return fmt.Sprintf("%04d-%02d", y.Year, int(y.Month))
+}
+
+// MonthsUntil returns the number of months from y to other, negative if other is earlier.
+func (y YearMonth) MonthsUntil(other YearMonth) int {
+	return (other.Year-y.Year)*12 + int(other.Month-y.Month)
+}
+
 type Repo struct {
 	Owner string
 	Name  string
@@ -86,6 +118,15 @@ func (r Repo) Validate() error {
 type Issue struct {
 	Name           string            `yaml:"name"`
 	CreationMonths []Month           `yaml:"creation_months"`
+	Schedule       *string           `yaml:"schedule,omitempty"` // Standard 5-field cron expression
+	Every          *Interval         `yaml:"every,omitempty"`
+	Day            *string           `yaml:"day,omitempty"` // e.g. "15", "2nd-tuesday", "last-business-day"
+	Years          []int             `yaml:"years,omitempty"`
+	YearParity     *string           `yaml:"year_parity,omitempty"`  // "odd" or "even"
+	ActiveFrom     *string           `yaml:"active_from,omitempty"`  // Format: "YYYY-MM-DD"
+	ActiveUntil    *string           `yaml:"active_until,omitempty"` // Format: "YYYY-MM-DD"
+	OnHoliday      *string           `yaml:"on_holiday,omitempty"`   // "skip", "next" or "previous"
+	Timezone       *string           `yaml:"timezone,omitempty"`     // IANA name, overrides defaults.timezone
 	TemplateFile   *string           `yaml:"template_file"`
 	TitlePrefix    *string           `yaml:"title_prefix,omitempty"`
 	TitleSuffix    *string           `yaml:"title_suffix,omitempty"`
@@ -94,6 +135,12 @@ type Issue struct {
 	TargetRepo     *string           `yaml:"target_repo,omitempty"` // Format: "owner/repo"
 }
 
+// Interval schedules an issue every Months months, counted from the Starting month.
+type Interval struct {
+	Months   int    `yaml:"months"`
+	Starting string `yaml:"starting"` // Format: "YYYY-MM"
+}
+
 func (i Issue) GetTargetRepo(defaults Defaults) (Repo, error) {
 	if i.TargetRepo != nil {
 		return ParseRepo(*i.TargetRepo)
@@ -101,10 +148,50 @@ func (i Issue) GetTargetRepo(defaults Defaults) (Repo, error) {
 	return defaults.GetTargetRepo()
 }
 
-type IssueToCreate = Issue
+// GetLocation returns the timezone of the issue, falling back to defaults.timezone.
+// It returns nil if neither is configured.
+func (i Issue) GetLocation(defaults Defaults) (*time.Location, error) {
+	if i.Timezone != nil {
+		return time.LoadLocation(*i.Timezone)
+	}
+	return defaults.GetLocation()
+}
+
+// calendarDay is the location of dates given without a time, such as --date 2025-03-01.
+// They stand for the same calendar day in every timezone, so InLocation rebuilds them in
+// the timezone of each issue instead of converting the instant.
+var calendarDay = time.FixedZone("calendar day", 0)
+
+// InLocation returns t in the timezone of the issue, or t unchanged if no timezone is configured.
+// A calendarDay date becomes the same date in the timezone of the issue, the local one by default.
+func (i Issue) InLocation(t time.Time, defaults Defaults) time.Time {
+	loc, err := i.GetLocation(defaults)
+	if err != nil {
+		loc = nil
+	}
+	if t.Location() == calendarDay {
+		if loc == nil {
+			loc = time.Local
+		}
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+	}
+	if loc == nil {
+		return t
+	}
+	return t.In(loc)
+}
+
+type IssueToCreate struct {
+	Issue
+	// Date is the occurrence date in the timezone of the issue; templates are rendered from it.
+	Date time.Time
+}
 
-func NewIssueToCreate(issue Issue, defaults Defaults) IssueToCreate {
-	issueToCreate := issue
+func NewIssueToCreate(issue Issue, defaults Defaults, date time.Time) IssueToCreate {
+	issueToCreate := IssueToCreate{
+		Issue: issue,
+		Date:  issue.InLocation(date, defaults),
+	}
 
 	if issue.ProjectID == nil {
 		projectID := defaults.ProjectID
@@ -138,7 +225,11 @@ type FieldUpdate struct {
 	FieldID   string  `json:"field_id"`
 	FieldType string  `json:"field_type"`
 	Value     *string `json:"value,omitempty"`
-	OptionID  *string `json:"option_id,omitempty"`
+	// Number is set for NUMBER fields
+	Number   *float64 `json:"number,omitempty"`
+	OptionID *string  `json:"option_id,omitempty"`
+	// IterationID is set for ITERATION fields
+	IterationID *string `json:"iteration_id,omitempty"`
 }
 
 type IssueOutput struct {
@@ -148,4 +239,10 @@ type IssueOutput struct {
 	ProjectID    *string       `json:"project_id"`
 	TargetRepo   *string       `json:"target_repo"`
 	FieldUpdates []FieldUpdate `json:"field_updates"`
+	// OccurrenceKey identifies the occurrence; Body embeds it as an HTML comment
+	OccurrenceKey string `json:"occurrence_key"`
+	Body          string `json:"body"`
+	// Status is "exists" when the issue has already been created for this period
+	Status           string `json:"status,omitempty"`
+	ExistingIssueURL string `json:"existing_issue_url,omitempty"`
 }
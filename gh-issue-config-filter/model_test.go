@@ -2,6 +2,7 @@ package main
 
 import (
 	"testing"
+	"time"
 )
 
 func TestMonth(t *testing.T) {
@@ -30,6 +31,34 @@ func TestMonth(t *testing.T) {
 	}
 }
 
+func TestYearMonth_MonthsUntil(t *testing.T) {
+	cases := []struct {
+		name   string
+		from   YearMonth
+		to     YearMonth
+		expect int
+	}{
+		{name: "same month", from: YearMonth{2025, March}, to: YearMonth{2025, March}, expect: 0},
+		{name: "same year", from: YearMonth{2025, March}, to: YearMonth{2025, July}, expect: 4},
+		{name: "across years", from: YearMonth{2024, November}, to: YearMonth{2025, February}, expect: 3},
+		{name: "earlier", from: YearMonth{2025, March}, to: YearMonth{2024, March}, expect: -12},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.from.MonthsUntil(tt.to); got != tt.expect {
+				t.Errorf("expected %d, got %d", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestYearMonthOf(t *testing.T) {
+	got := YearMonthOf(time.Date(2025, time.March, 20, 0, 0, 0, 0, time.UTC))
+	if got.String() != "2025-03" {
+		t.Errorf("expected %q, got %q", "2025-03", got.String())
+	}
+}
+
 func TestIssue_IsCreationMonth(t *testing.T) {
 
 	cases := []struct {
@@ -84,7 +113,8 @@ func TestNewIssueToCreate(t *testing.T) {
 	issue := Issue{
 		Name: "test",
 	}
-	issueToCreate := NewIssueToCreate(issue, defaults)
+	date := time.Date(2025, time.January, 31, 20, 0, 0, 0, time.UTC)
+	issueToCreate := NewIssueToCreate(issue, defaults, date)
 
 	if issueToCreate.ProjectID == nil {
 		t.Errorf("expected ProjectID to be set, got nil")
@@ -97,4 +127,46 @@ func TestNewIssueToCreate(t *testing.T) {
 	} else if *issueToCreate.TargetRepo != "default/repo" {
 		t.Errorf("expected TargetRepo to be %q, got %q", "default/repo", *issueToCreate.TargetRepo)
 	}
+
+	if !issueToCreate.Date.Equal(date) {
+		t.Errorf("expected Date to be %v, got %v", date, issueToCreate.Date)
+	}
+}
+
+func TestIssue_InLocation(t *testing.T) {
+	date := time.Date(2025, time.January, 31, 20, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name      string
+		issue     Issue
+		defaults  Defaults
+		expectDay string
+	}{
+		{
+			name:      "no timezone keeps the input",
+			issue:     Issue{},
+			defaults:  Defaults{},
+			expectDay: "2025-01-31",
+		},
+		{
+			name:      "default timezone",
+			issue:     Issue{},
+			defaults:  Defaults{Timezone: stringPtr("Asia/Tokyo")},
+			expectDay: "2025-02-01",
+		},
+		{
+			name:      "issue timezone overrides default",
+			issue:     Issue{Timezone: stringPtr("America/Los_Angeles")},
+			defaults:  Defaults{Timezone: stringPtr("Asia/Tokyo")},
+			expectDay: "2025-01-31",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.issue.InLocation(date, tt.defaults)
+			if got.Format("2006-01-02") != tt.expectDay {
+				t.Errorf("expected %s, got %s", tt.expectDay, got.Format("2006-01-02"))
+			}
+		})
+	}
 }
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// occurrenceMarkerPrefix starts the HTML comment embedded in the body of every created issue.
+const occurrenceMarkerPrefix = "recurring-backlog-item occurrence-key:"
+
+var occurrenceMarkerPattern = regexp.MustCompile(`<!-- ` + regexp.QuoteMeta(occurrenceMarkerPrefix) + ` ([0-9a-f]+) -->`)
+
+// OccurrenceKey returns a key identifying one occurrence of an issue: the same issue name,
+// target repo and period always give the same key, however the title is rendered or edited.
+func (i IssueToCreate) OccurrenceKey(defaults Defaults) (string, error) {
+	repo, err := i.GetTargetRepo(defaults)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{i.Name, strings.ToLower(repo.String()), i.PeriodOf(i.Date)}, "\x00")))
+	return hex.EncodeToString(sum[:16]), nil
+}
+
+// OccurrenceMarker returns the HTML comment embedded in issue bodies to find them by occurrence key.
+func OccurrenceMarker(key string) string {
+	return fmt.Sprintf("<!-- %s %s -->", occurrenceMarkerPrefix, key)
+}
+
+// OccurrenceKeyOf extracts the occurrence key from an issue body.
+func OccurrenceKeyOf(body string) (string, bool) {
+	match := occurrenceMarkerPattern.FindStringSubmatch(body)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// buildBody reads the template file of an issue and appends the occurrence marker to it.
+func buildBody(issue IssueToCreate, key string) (string, error) {
+	if issue.TemplateFile == nil {
+		return "", fmt.Errorf("template_file is not set for issue %s", issue.Name)
+	}
+	template, err := os.ReadFile(*issue.TemplateFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read template file for issue %s: %w", issue.Name, err)
+	}
+	return strings.TrimRight(string(template), "\n") + "\n\n" + OccurrenceMarker(key) + "\n", nil
+}
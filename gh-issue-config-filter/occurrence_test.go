@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIssueToCreate_OccurrenceKey(t *testing.T) {
+	defaults := Defaults{ProjectID: "default_project_id", TargetRepo: "owner/repo"}
+	monthly := Issue{Name: "Monthly Report", CreationMonths: []Month{March, April}}
+	weekly := Issue{Name: "Weekly Sync", Schedule: stringPtr("0 9 * * MON")}
+	mar3 := time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC)
+	mar10 := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+	apr1 := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	key := func(issue Issue, defaults Defaults, date time.Time) string {
+		t.Helper()
+		k, err := NewIssueToCreate(issue, defaults, date).OccurrenceKey(defaults)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return k
+	}
+
+	cases := []struct {
+		name       string
+		a          string
+		b          string
+		expectSame bool
+	}{
+		{
+			name:       "same month for a month-level schedule",
+			a:          key(monthly, defaults, mar3),
+			b:          key(monthly, defaults, mar10),
+			expectSame: true,
+		},
+		{
+			name:       "different months",
+			a:          key(monthly, defaults, mar3),
+			b:          key(monthly, defaults, apr1),
+			expectSame: false,
+		},
+		{
+			name:       "different days for a day-level schedule",
+			a:          key(weekly, defaults, mar3),
+			b:          key(weekly, defaults, mar10),
+			expectSame: false,
+		},
+		{
+			name:       "title templates do not matter",
+			a:          key(monthly, defaults, mar3),
+			b:          key(Issue{Name: "Monthly Report", CreationMonths: []Month{March}, TitleSuffix: stringPtr("{{YearMonth}}")}, defaults, mar3),
+			expectSame: true,
+		},
+		{
+			name:       "repo case does not matter",
+			a:          key(monthly, defaults, mar3),
+			b:          key(monthly, Defaults{ProjectID: "default_project_id", TargetRepo: "Owner/Repo"}, mar3),
+			expectSame: true,
+		},
+		{
+			name:       "different repos",
+			a:          key(monthly, defaults, mar3),
+			b:          key(monthly, Defaults{ProjectID: "default_project_id", TargetRepo: "other/repo"}, mar3),
+			expectSame: false,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if (tt.a == tt.b) != tt.expectSame {
+				t.Errorf("expected same=%v, got %q and %q", tt.expectSame, tt.a, tt.b)
+			}
+		})
+	}
+}
+
+func TestOccurrenceKeyOf(t *testing.T) {
+	body := "## Checklist\n\n- [ ] done\n\n" + OccurrenceMarker("0123abcd") + "\n"
+	key, ok := OccurrenceKeyOf(body)
+	if !ok || key != "0123abcd" {
+		t.Errorf("expected key %q, got %q (ok=%v)", "0123abcd", key, ok)
+	}
+	if _, ok := OccurrenceKeyOf("no marker here"); ok {
+		t.Error("expected no key in a body without marker")
+	}
+}
+
+func TestBuildBody(t *testing.T) {
+	templateFile := filepath.Join(t.TempDir(), "template.md")
+	if err := os.WriteFile(templateFile, []byte("## Tasks\n- [ ] wash\n\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	issue := IssueToCreate{Issue: Issue{Name: "Wash My Cat", TemplateFile: &templateFile}}
+	body, err := buildBody(issue, "0123abcd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "## Tasks\n- [ ] wash\n\n<!-- recurring-backlog-item occurrence-key: 0123abcd -->\n"
+	if body != expected {
+		t.Errorf("expected %q, got %q", expected, body)
+	}
+
+	missing := IssueToCreate{Issue: Issue{Name: "Missing", TemplateFile: stringPtr(filepath.Join(t.TempDir(), "missing.md"))}}
+	if _, err := buildBody(missing, "0123abcd"); err == nil {
+		t.Error("expected error for a missing template file, got nil")
+	}
+}
+
+func TestFindIssueByOccurrenceKey(t *testing.T) {
+	client := &mockGitHubClient{existingIssues: map[string][]ExistingIssue{
+		"owner/repo": {
+			{Number: 1, Title: "Mentions 0123abcd in text", Body: "see 0123abcd"},
+			{Number: 2, Title: "Edited title", Body: "body\n\n" + OccurrenceMarker("0123abcd")},
+		},
+	}}
+	repo := Repo{Owner: "owner", Name: "repo"}
+
+	issue, found, err := FindIssueByOccurrenceKey(context.Background(), client, repo, "0123abcd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !found || issue.Number != 2 {
+		t.Errorf("expected issue #2, got #%d (found=%v)", issue.Number, found)
+	}
+	if expected := `repo:owner/repo is:issue in:body "0123abcd"`; client.searchQueries[0] != expected {
+		t.Errorf("expected query %q, got %q", expected, client.searchQueries[0])
+	}
+
+	if _, found, _ := FindIssueByOccurrenceKey(context.Background(), client, repo, "ffff"); found {
+		t.Error("expected no issue for an unknown key")
+	}
+}
@@ -7,12 +7,34 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
 )
 
-func outputJSON(ctx context.Context, issuesToCreate IssuesToCreate, defaults Defaults, ghClient GitHubClient) error {
+// outputJSON writes the issues to create as JSON to stdout, resolving project fields and
+// dropping or flagging issues that already exist according to existingPolicy.
+// It returns the issues it wrote.
+func outputJSON(ctx context.Context, issuesToCreate IssuesToCreate, defaults Defaults, ghClient GitHubClient, existingPolicy string) ([]IssueOutput, error) {
+	output, err := BuildIssueOutputs(ctx, issuesToCreate, defaults, ghClient)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err = CheckExistingIssues(ctx, issuesToCreate, output, defaults, ghClient, existingPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return output, encoder.Encode(output)
+}
+
+// BuildIssueOutputs renders titles and bodies and resolves field IDs and option IDs for each issue to create.
+func BuildIssueOutputs(ctx context.Context, issuesToCreate IssuesToCreate, defaults Defaults, ghClient GitHubClient) ([]IssueOutput, error) {
 	output := make([]IssueOutput, 0, len(issuesToCreate.Issues))
 
 	// Track projects we've already logged
@@ -22,7 +44,7 @@ func outputJSON(ctx context.Context, issuesToCreate IssuesToCreate, defaults Def
 		// Get target repo
 		repo, err := issue.GetTargetRepo(defaults)
 		if err != nil {
-			return fmt.Errorf("failed to get target repo for issue %s: %w", issue.Name, err)
+			return nil, fmt.Errorf("failed to get target repo for issue %s: %w", issue.Name, err)
 		}
 
 		// Get project ID
@@ -43,24 +65,25 @@ func outputJSON(ctx context.Context, issuesToCreate IssuesToCreate, defaults Def
 			loggedProjects[projectID] = true
 		}
 
-		// Get project fields
-		projectFields, err := ghClient.GetProjectFields(ctx, projectID, repo.Owner)
+		// Get project fields by name for quick lookup
+		fieldMap, err := ProjectFieldMap(ctx, ghClient, projectID, repo.Owner)
 		if err != nil {
-			return fmt.Errorf("failed to get project fields for issue %s: %w", issue.Name, err)
+			return nil, fmt.Errorf("failed to get project fields for issue %s: %w", issue.Name, err)
 		}
 
-		// Create field map for quick lookup
-		fieldMap := make(map[string]ProjectField)
-		for _, field := range projectFields {
-			fieldMap[field.Name] = field
+		// Build field_updates array, in field name order so that the same config always gives
+		// the same output, plan and content hash
+		fieldNames := make([]string, 0, len(issue.Fields))
+		for fieldName := range issue.Fields {
+			fieldNames = append(fieldNames, fieldName)
 		}
-
-		// Build field_updates array
+		sort.Strings(fieldNames)
 		fieldUpdates := make([]FieldUpdate, 0, len(issue.Fields))
-		for fieldName, fieldValue := range issue.Fields {
-			field, exists := fieldMap[fieldName]
-			if !exists {
-				return fmt.Errorf("field '%s' not found in project for issue %s", fieldName, issue.Name)
+		for _, fieldName := range fieldNames {
+			fieldValue := issue.Fields[fieldName]
+			field, err := LookupField(fieldMap, fieldName, defaults.GetMatch(), projectID)
+			if err != nil {
+				return nil, fmt.Errorf("issue %s: %w", issue.Name, err)
 			}
 
 			fieldUpdate := FieldUpdate{
@@ -68,106 +91,135 @@ func outputJSON(ctx context.Context, issuesToCreate IssuesToCreate, defaults Def
 				FieldType: field.DataType,
 			}
 
+			// Render templates such as {{YearMonth}} for the occurrence. DATE fields have their own,
+			// which are rendered below.
+			if field.DataType != "DATE" {
+				fieldValue, err = expandFieldValue(fieldValue, issue.Date)
+				if err != nil {
+					return nil, fmt.Errorf("field '%s' for issue %s: %w", fieldName, issue.Name, err)
+				}
+			}
+
 			// Handle different field types
 			switch field.DataType {
-			case "TEXT", "NUMBER":
+			case "TEXT":
 				fieldUpdate.Value = &fieldValue
+			case "NUMBER":
+				number, err := ParseNumberField(fieldValue)
+				if err != nil {
+					return nil, fmt.Errorf("field '%s' in project '%s' for issue %s: %w", fieldName, projectID, issue.Name, err)
+				}
+				fieldUpdate.Number = &number
 			case "SINGLE_SELECT":
 				// Find option ID by name
-				var optionID *string
-				for _, opt := range field.Options {
-					if opt.Name == fieldValue {
-						optionID = &opt.ID
-						break
-					}
+				option, err := LookupOption(field, fieldValue, defaults.GetMatch())
+				if err != nil {
+					return nil, fmt.Errorf("issue %s: %w", issue.Name, err)
 				}
-				if optionID == nil {
-					return fmt.Errorf("option '%s' not found in field '%s' for issue %s", fieldValue, fieldName, issue.Name)
+				fieldUpdate.OptionID = &option.ID
+			case "DATE":
+				// Render relative dates for the occurrence
+				date, err := ExpandDateField(fieldValue, issue.Date)
+				if err != nil {
+					return nil, fmt.Errorf("field '%s' for issue %s: %w", fieldName, issue.Name, err)
 				}
-				fieldUpdate.OptionID = optionID
+				fieldUpdate.Value = &date
+			case "ITERATION":
+				// Resolve current, next and @+N for the occurrence
+				iteration, err := ResolveIteration(field, fieldValue, issue.Date)
+				if err != nil {
+					return nil, fmt.Errorf("field '%s' for issue %s: %w", fieldName, issue.Name, err)
+				}
+				fieldUpdate.IterationID = &iteration.ID
 			default:
-				return fmt.Errorf("unsupported field type '%s' for field '%s' in issue %s", field.DataType, fieldName, issue.Name)
+				return nil, fmt.Errorf("unsupported field type '%s' for field '%s' in issue %s", field.DataType, fieldName, issue.Name)
 			}
 
 			fieldUpdates = append(fieldUpdates, fieldUpdate)
 		}
 
-		// Generate title from name, title_prefix, and title_suffix
-		expandedPrefix, err := expandTitlePrefix(issue.TitlePrefix)
+		title, err := buildTitle(issue)
 		if err != nil {
-			return fmt.Errorf("failed to expand title_prefix for issue %s: %w", issue.Name, err)
+			return nil, err
 		}
 
-		expandedSuffix, err := expandTitleSuffix(issue.TitleSuffix)
+		key, err := issue.OccurrenceKey(defaults)
 		if err != nil {
-			return fmt.Errorf("failed to expand title_suffix for issue %s: %w", issue.Name, err)
+			return nil, fmt.Errorf("failed to compute occurrence key for issue %s: %w", issue.Name, err)
 		}
 
-		// Build title: "{prefix} {name} {suffix}"
-		// Add space between prefix and name only if prefix doesn't end with space
-		// Add space between name and suffix only if suffix doesn't start with space
-		title := issue.Name
-		if expandedPrefix != "" {
-			if strings.HasSuffix(expandedPrefix, " ") {
-				title = expandedPrefix + title
-			} else {
-				title = expandedPrefix + " " + title
-			}
-		}
-		if expandedSuffix != "" {
-			if strings.HasPrefix(expandedSuffix, " ") {
-				title = title + expandedSuffix
-			} else {
-				title = title + " " + expandedSuffix
-			}
+		body, err := buildBody(issue, key)
+		if err != nil {
+			return nil, err
 		}
 
 		item := IssueOutput{
-			Name:         issue.Name,
-			Title:        title,
-			TemplateFile: issue.TemplateFile,
-			ProjectID:    issue.ProjectID,
-			TargetRepo:   issue.TargetRepo,
-			FieldUpdates: fieldUpdates,
+			Name:          issue.Name,
+			Title:         title,
+			TemplateFile:  issue.TemplateFile,
+			ProjectID:     issue.ProjectID,
+			TargetRepo:    issue.TargetRepo,
+			FieldUpdates:  fieldUpdates,
+			OccurrenceKey: key,
+			Body:          body,
 		}
 
 		output = append(output, item)
 	}
 
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(output)
+	return output, nil
+}
+
+// buildTitle generates the title of an issue from its name, title_prefix and title_suffix,
+// rendering templates with the occurrence date of the issue.
+func buildTitle(issue IssueToCreate) (string, error) {
+	expandedPrefix, err := expandTitlePrefix(issue.TitlePrefix, issue.Date)
+	if err != nil {
+		return "", fmt.Errorf("failed to expand title_prefix for issue %s: %w", issue.Name, err)
+	}
+
+	expandedSuffix, err := expandTitleSuffix(issue.TitleSuffix, issue.Date)
+	if err != nil {
+		return "", fmt.Errorf("failed to expand title_suffix for issue %s: %w", issue.Name, err)
+	}
+
+	// Build title: "{prefix} {name} {suffix}"
+	// Add space between prefix and name only if prefix doesn't end with space
+	// Add space between name and suffix only if suffix doesn't start with space
+	title := issue.Name
+	if expandedPrefix != "" {
+		if strings.HasSuffix(expandedPrefix, " ") {
+			title = expandedPrefix + title
+		} else {
+			title = expandedPrefix + " " + title
+		}
+	}
+	if expandedSuffix != "" {
+		if strings.HasPrefix(expandedSuffix, " ") {
+			title = title + expandedSuffix
+		} else {
+			title = title + " " + expandedSuffix
+		}
+	}
+
+	return title, nil
 }
 
 // expandTitleTemplate expands template variables in a title template string.
+// Dates are rendered from date, the occurrence date in the timezone of the issue.
 // If templateStr is nil or empty, returns an empty string.
 // Supported template functions:
 //   - {{Date}} - Current date in YYYY-MM-DD format
 //   - {{Year}} - Current year (e.g., 2025)
 //   - {{Month}} - Current month (e.g., 01)
 //   - {{YearMonth}} - Current year and month in YYYY-MM format
-func expandTitleTemplate(templateStr *string, templateName string) (string, error) {
+//   - {{Quarter}} - Current quarter (1-4)
+func expandTitleTemplate(templateStr *string, templateName string, date time.Time) (string, error) {
 	if templateStr == nil || *templateStr == "" {
 		return "", nil
 	}
 
-	now := time.Now()
-	funcMap := template.FuncMap{
-		"Date": func() string {
-			return now.Format("2006-01-02")
-		},
-		"Year": func() string {
-			return now.Format("2006")
-		},
-		"Month": func() string {
-			return now.Format("01")
-		},
-		"YearMonth": func() string {
-			return now.Format("2006-01")
-		},
-	}
-
-	tmpl, err := template.New("title").Funcs(funcMap).Parse(*templateStr)
+	tmpl, err := template.New("title").Funcs(titleTemplateFuncs(date)).Parse(*templateStr)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse %s template: %w", templateName, err)
 	}
@@ -181,14 +233,41 @@ func expandTitleTemplate(templateStr *string, templateName string) (string, erro
 	return buf.String(), nil
 }
 
+// titleTemplateFuncs returns the functions of title templates, rendering dates from date.
+func titleTemplateFuncs(date time.Time) template.FuncMap {
+	return template.FuncMap{
+		"Date": func() string {
+			return date.Format("2006-01-02")
+		},
+		"Year": func() string {
+			return date.Format("2006")
+		},
+		"Month": func() string {
+			return date.Format("01")
+		},
+		"YearMonth": func() string {
+			return date.Format("2006-01")
+		},
+		"Quarter": func() string {
+			return strconv.Itoa((int(date.Month())-1)/3 + 1)
+		},
+	}
+}
+
+// expandFieldValue renders the templates in a project field value for the occurrence date,
+// with the same functions as titles. Values without templates are returned as is.
+func expandFieldValue(value string, date time.Time) (string, error) {
+	return expandTitleTemplate(&value, "field value", date)
+}
+
 // expandTitleSuffix expands template variables in title_suffix and returns the expanded suffix.
 // If titleSuffix is nil or empty, returns an empty string.
-func expandTitleSuffix(titleSuffix *string) (string, error) {
-	return expandTitleTemplate(titleSuffix, "title_suffix")
+func expandTitleSuffix(titleSuffix *string, date time.Time) (string, error) {
+	return expandTitleTemplate(titleSuffix, "title_suffix", date)
 }
 
 // expandTitlePrefix expands template variables in title_prefix and returns the expanded prefix.
 // If titlePrefix is nil or empty, returns an empty string.
-func expandTitlePrefix(titlePrefix *string) (string, error) {
-	return expandTitleTemplate(titlePrefix, "title_prefix")
+func expandTitlePrefix(titlePrefix *string, date time.Time) (string, error) {
+	return expandTitleTemplate(titlePrefix, "title_prefix", date)
 }
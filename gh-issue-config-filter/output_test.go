@@ -1,14 +1,16 @@
 package main
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"regexp"
-	"strings"
 	"testing"
 	"time"
 )
 
 func TestExpandTitlePrefix(t *testing.T) {
-	now := time.Now()
+	now := time.Date(2025, time.March, 7, 9, 30, 0, 0, time.UTC)
 	expectedYear := now.Format("2006")
 	expectedMonth := now.Format("01")
 	expectedYearMonth := now.Format("2006-01")
@@ -78,7 +80,7 @@ func TestExpandTitlePrefix(t *testing.T) {
 
 	for _, tt := range cases {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := expandTitlePrefix(tt.titlePrefix)
+			got, err := expandTitlePrefix(tt.titlePrefix, now)
 			if tt.expectError {
 				if err == nil {
 					t.Errorf("expected error, got nil")
@@ -96,7 +98,7 @@ func TestExpandTitlePrefix(t *testing.T) {
 }
 
 func TestExpandTitleSuffix(t *testing.T) {
-	now := time.Now()
+	now := time.Date(2025, time.March, 7, 9, 30, 0, 0, time.UTC)
 	expectedYear := now.Format("2006")
 	expectedMonth := now.Format("01")
 	expectedYearMonth := now.Format("2006-01")
@@ -166,7 +168,7 @@ func TestExpandTitleSuffix(t *testing.T) {
 
 	for _, tt := range cases {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := expandTitleSuffix(tt.titleSuffix)
+			got, err := expandTitleSuffix(tt.titleSuffix, now)
 			if tt.expectError {
 				if err == nil {
 					t.Errorf("expected error, got nil")
@@ -184,7 +186,7 @@ func TestExpandTitleSuffix(t *testing.T) {
 }
 
 func TestBuildTitleWithPrefixAndSuffix(t *testing.T) {
-	now := time.Now()
+	now := time.Date(2025, time.March, 7, 9, 30, 0, 0, time.UTC)
 	expectedYear := now.Format("2006")
 	expectedYearMonth := now.Format("2006-01")
 
@@ -280,15 +282,15 @@ func TestBuildTitleWithPrefixAndSuffix(t *testing.T) {
 
 	for _, tt := range cases {
 		t.Run(tt.name, func(t *testing.T) {
-			expandedPrefix, err := expandTitlePrefix(tt.titlePrefix)
-			if err != nil && !tt.expectError {
-				t.Fatalf("unexpected error expanding prefix: %v", err)
+			issue := IssueToCreate{
+				Issue: Issue{
+					Name:        tt.issueName,
+					TitlePrefix: tt.titlePrefix,
+					TitleSuffix: tt.titleSuffix,
+				},
+				Date: now,
 			}
-			if err == nil && tt.expectError {
-				// If we expect an error, it should come from suffix expansion
-			}
-
-			expandedSuffix, err := expandTitleSuffix(tt.titleSuffix)
+			title, err := buildTitle(issue)
 			if tt.expectError {
 				if err == nil {
 					t.Errorf("expected error, got nil")
@@ -296,26 +298,7 @@ func TestBuildTitleWithPrefixAndSuffix(t *testing.T) {
 				return
 			}
 			if err != nil {
-				t.Fatalf("unexpected error expanding suffix: %v", err)
-			}
-
-			// Build title: "{prefix} {name} {suffix}"
-			// Add space between prefix and name only if prefix doesn't end with space
-			// Add space between name and suffix only if suffix doesn't start with space
-			title := tt.issueName
-			if expandedPrefix != "" {
-				if strings.HasSuffix(expandedPrefix, " ") {
-					title = expandedPrefix + title
-				} else {
-					title = expandedPrefix + " " + title
-				}
-			}
-			if expandedSuffix != "" {
-				if strings.HasPrefix(expandedSuffix, " ") {
-					title = title + expandedSuffix
-				} else {
-					title = title + " " + expandedSuffix
-				}
+				t.Fatalf("unexpected error building title: %v", err)
 			}
 
 			if title != tt.expect {
@@ -326,7 +309,7 @@ func TestBuildTitleWithPrefixAndSuffix(t *testing.T) {
 }
 
 func TestExpandTitleTemplate(t *testing.T) {
-	now := time.Now()
+	now := time.Date(2025, time.March, 7, 9, 30, 0, 0, time.UTC)
 	expectedYear := now.Format("2006")
 
 	cases := []struct {
@@ -365,6 +348,13 @@ func TestExpandTitleTemplate(t *testing.T) {
 			expect:       expectedYear,
 			expectError:  false,
 		},
+		{
+			name:         "template with Quarter",
+			templateStr:  stringPtr("Q{{Quarter}}"),
+			templateName: "test",
+			expect:       "Q1",
+			expectError:  false,
+		},
 		{
 			name:         "invalid template function",
 			templateStr:  stringPtr("{{Invalid}}"),
@@ -385,7 +375,7 @@ func TestExpandTitleTemplate(t *testing.T) {
 
 	for _, tt := range cases {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := expandTitleTemplate(tt.templateStr, tt.templateName)
+			got, err := expandTitleTemplate(tt.templateStr, tt.templateName, now)
 			if tt.expectError {
 				if err == nil {
 					t.Errorf("expected error, got nil")
@@ -403,3 +393,183 @@ func TestExpandTitleTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildIssueOutputs_DateFields(t *testing.T) {
+	templateFile := filepath.Join(t.TempDir(), "template.md")
+	if err := os.WriteFile(templateFile, []byte("## Tasks\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	defaults := Defaults{ProjectID: "PVT_1", TargetRepo: "owner/repo"}
+	issue := Issue{
+		Name:           "Close the books",
+		CreationMonths: []Month{January, February},
+		TemplateFile:   &templateFile,
+		Fields:         map[string]string{"Due date": "{{EndOfMonth}}"},
+	}
+	fields := []ProjectField{{ID: "PVTF_due", Name: "Due date", DataType: "DATE"}}
+
+	issuesToCreate := IssuesToCreate{Issues: []IssueToCreate{
+		NewIssueToCreate(issue, defaults, time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)),
+		NewIssueToCreate(issue, defaults, time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)),
+	}}
+	outputs, err := BuildIssueOutputs(context.Background(), issuesToCreate, defaults, newMockGitHubClient(fields))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Each occurrence gets its own due date
+	for i, expected := range []string{"2024-02-29", "2025-02-28"} {
+		updates := outputs[i].FieldUpdates
+		if len(updates) != 1 || updates[0].FieldType != "DATE" || updates[0].Value == nil || *updates[0].Value != expected {
+			t.Errorf("outputs[%d]: expected due date %s, got %+v", i, expected, updates)
+		}
+	}
+}
+
+func TestBuildIssueOutputs_NumberFields(t *testing.T) {
+	templateFile := filepath.Join(t.TempDir(), "template.md")
+	if err := os.WriteFile(templateFile, []byte("## Tasks\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	defaults := Defaults{ProjectID: "PVT_1", TargetRepo: "owner/repo"}
+	fields := []ProjectField{{ID: "PVTF_sp", Name: "SP", DataType: "NUMBER"}}
+	date := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	issue := Issue{Name: "Estimate", CreationMonths: []Month{January}, TemplateFile: &templateFile, Fields: map[string]string{"SP": "3"}}
+	outputs, err := BuildIssueOutputs(context.Background(), IssuesToCreate{Issues: []IssueToCreate{NewIssueToCreate(issue, defaults, date)}}, defaults, newMockGitHubClient(fields))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	update := outputs[0].FieldUpdates[0]
+	if update.Number == nil || *update.Number != 3 || update.Value != nil {
+		t.Errorf("expected the number 3, got %+v", update)
+	}
+
+	issue.Fields = map[string]string{"SP": "five"}
+	_, err = BuildIssueOutputs(context.Background(), IssuesToCreate{Issues: []IssueToCreate{NewIssueToCreate(issue, defaults, date)}}, defaults, newMockGitHubClient(fields))
+	if err == nil || !regexp.MustCompile(`field 'SP' in project 'PVT_1' for issue Estimate: value 'five' is not a number`).MatchString(err.Error()) {
+		t.Errorf("expected a type mismatch naming the field and project, got %v", err)
+	}
+}
+
+func TestBuildIssueOutputs_CaseInsensitiveMatch(t *testing.T) {
+	templateFile := filepath.Join(t.TempDir(), "template.md")
+	if err := os.WriteFile(templateFile, []byte("## Tasks\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	defaults := Defaults{ProjectID: "PVT_1", TargetRepo: "owner/repo", Match: stringPtr(MatchCaseInsensitive)}
+	fields := []ProjectField{{
+		ID:       "PVTF_status",
+		Name:     "Status",
+		DataType: "SINGLE_SELECT",
+		Options:  []ProjectFieldOption{{ID: "OPT_ready", Name: "Ready"}},
+	}}
+	date := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	issue := Issue{Name: "Triage", CreationMonths: []Month{January}, TemplateFile: &templateFile, Fields: map[string]string{"STATUS": "ready"}}
+	outputs, err := BuildIssueOutputs(context.Background(), IssuesToCreate{Issues: []IssueToCreate{NewIssueToCreate(issue, defaults, date)}}, defaults, newMockGitHubClient(fields))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	update := outputs[0].FieldUpdates[0]
+	if update.FieldID != "PVTF_status" || update.OptionID == nil || *update.OptionID != "OPT_ready" {
+		t.Errorf("expected the Ready option of Status, got %+v", update)
+	}
+
+	defaults.Match = nil
+	_, err = BuildIssueOutputs(context.Background(), IssuesToCreate{Issues: []IssueToCreate{NewIssueToCreate(issue, defaults, date)}}, defaults, newMockGitHubClient(fields))
+	if err == nil || !regexp.MustCompile(`issue Triage: field 'STATUS' does not exist in project 'PVT_1'; did you mean 'Status'\?`).MatchString(err.Error()) {
+		t.Errorf("expected a suggestion for the field name, got %v", err)
+	}
+}
+
+func TestBuildIssueOutputs_TemplatedFields(t *testing.T) {
+	templateFile := filepath.Join(t.TempDir(), "template.md")
+	if err := os.WriteFile(templateFile, []byte("## Tasks\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	defaults := Defaults{ProjectID: "PVT_1", TargetRepo: "owner/repo"}
+	issue := Issue{
+		Name:           "Quarterly Planning",
+		CreationMonths: []Month{March, December},
+		TemplateFile:   &templateFile,
+		Fields: map[string]string{
+			"Period":   "{{YearMonth}}",
+			"Estimate": `{{if eq Quarter "4"}}8{{else}}3{{end}}`,
+			"Stage":    "Q{{Quarter}}",
+		},
+	}
+	fields := []ProjectField{
+		{ID: "PVTF_period", Name: "Period", DataType: "TEXT"},
+		{ID: "PVTF_estimate", Name: "Estimate", DataType: "NUMBER"},
+		{ID: "PVTF_stage", Name: "Stage", DataType: "SINGLE_SELECT", Options: []ProjectFieldOption{
+			{ID: "OPT_q1", Name: "Q1"},
+			{ID: "OPT_q4", Name: "Q4"},
+		}},
+	}
+
+	issuesToCreate := IssuesToCreate{Issues: []IssueToCreate{
+		NewIssueToCreate(issue, defaults, time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)),
+		NewIssueToCreate(issue, defaults, time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)),
+	}}
+	outputs, err := BuildIssueOutputs(context.Background(), issuesToCreate, defaults, newMockGitHubClient(fields))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		period   string
+		estimate float64
+		option   string
+	}{
+		{period: "2025-03", estimate: 3, option: "OPT_q1"},
+		{period: "2025-12", estimate: 8, option: "OPT_q4"},
+	}
+	for i, want := range expected {
+		for _, update := range outputs[i].FieldUpdates {
+			switch update.FieldID {
+			case "PVTF_period":
+				if update.Value == nil || *update.Value != want.period {
+					t.Errorf("outputs[%d]: expected period %s, got %+v", i, want.period, update)
+				}
+			case "PVTF_estimate":
+				if update.Number == nil || *update.Number != want.estimate {
+					t.Errorf("outputs[%d]: expected estimate %v, got %+v", i, want.estimate, update)
+				}
+			case "PVTF_stage":
+				if update.OptionID == nil || *update.OptionID != want.option {
+					t.Errorf("outputs[%d]: expected option %s, got %+v", i, want.option, update)
+				}
+			}
+		}
+	}
+}
+
+func TestBuildIssueOutputs_FieldOrder(t *testing.T) {
+	templateFile := filepath.Join(t.TempDir(), "template.md")
+	if err := os.WriteFile(templateFile, []byte("## Tasks\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	defaults := Defaults{ProjectID: "PVT_1", TargetRepo: "owner/repo"}
+	names := []string{"Area", "Budget", "Customer", "Due", "Estimate", "Focus", "Goal", "Hours"}
+	issue := Issue{Name: "Ordered", CreationMonths: []Month{January}, TemplateFile: &templateFile, Fields: map[string]string{}}
+	var fields []ProjectField
+	for _, name := range names {
+		issue.Fields[name] = "value"
+		fields = append(fields, ProjectField{ID: "PVTF_" + name, Name: name, DataType: "TEXT"})
+	}
+	date := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	// Field updates come in field name order every time, whatever the map order
+	for range 5 {
+		outputs, err := BuildIssueOutputs(context.Background(), IssuesToCreate{Issues: []IssueToCreate{NewIssueToCreate(issue, defaults, date)}}, defaults, newMockGitHubClient(fields))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i, update := range outputs[0].FieldUpdates {
+			if update.FieldID != "PVTF_"+names[i] {
+				t.Fatalf("field_updates[%d]: expected PVTF_%s, got %s", i, names[i], update.FieldID)
+			}
+		}
+	}
+}
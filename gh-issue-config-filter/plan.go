@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// PlanVersion is the version of the plan file format written by this build.
+const PlanVersion = 1
+
+// ErrPlanStale is returned when the config, templates or project fields changed since the plan was made.
+var ErrPlanStale = errors.New("plan is stale")
+
+// Plan records exactly what apply will create, together with hashes of its inputs so that
+// apply can refuse to run when they changed after the plan was reviewed.
+type Plan struct {
+	Version    int       `json:"version"`
+	CreatedAt  time.Time `json:"created_at"`
+	ConfigFile string    `json:"config_file"`
+	ConfigHash string    `json:"config_hash"`
+	// TemplateHashes maps each template file used by the issues to its hash
+	TemplateHashes map[string]string `json:"template_hashes"`
+	Projects       []PlanProject     `json:"projects"`
+	Issues         []IssueOutput     `json:"issues"`
+	// ContentHash covers every other field of the plan
+	ContentHash string `json:"content_hash"`
+	// Signature is an HMAC of ContentHash, set when the plan is made with a signing key
+	Signature string `json:"signature,omitempty"`
+}
+
+// PlanProject is a project the plan adds items to, with a hash of its field schema.
+type PlanProject struct {
+	ID              string `json:"id"`
+	Owner           string `json:"owner"`
+	FieldSchemaHash string `json:"field_schema_hash"`
+}
+
+// NewPlan builds a plan for the issues to create. If signingKey is set, the plan is signed with it.
+func NewPlan(ctx context.Context, configFile string, outputs []IssueOutput, ghClient GitHubClient, now time.Time, signingKey []byte) (Plan, error) {
+	configHash, err := hashFile(configFile)
+	if err != nil {
+		return Plan{}, fmt.Errorf("failed to hash config file: %w", err)
+	}
+
+	plan := Plan{
+		Version:        PlanVersion,
+		CreatedAt:      now.UTC().Truncate(time.Second),
+		ConfigFile:     configFile,
+		ConfigHash:     configHash,
+		TemplateHashes: make(map[string]string),
+		Projects:       []PlanProject{},
+		Issues:         outputs,
+	}
+
+	projectOwners := make(map[string]string)
+	for _, output := range outputs {
+		if output.TemplateFile != nil {
+			if _, ok := plan.TemplateHashes[*output.TemplateFile]; !ok {
+				hash, err := hashFile(*output.TemplateFile)
+				if err != nil {
+					return Plan{}, fmt.Errorf("failed to hash template file: %w", err)
+				}
+				plan.TemplateHashes[*output.TemplateFile] = hash
+			}
+		}
+		if output.ProjectID == nil || output.TargetRepo == nil {
+			return Plan{}, fmt.Errorf("issue '%s' has no target_repo or project_id", output.Title)
+		}
+		if _, ok := projectOwners[*output.ProjectID]; !ok {
+			repo, err := ParseRepo(*output.TargetRepo)
+			if err != nil {
+				return Plan{}, fmt.Errorf("issue '%s': invalid target_repo: %w", output.Title, err)
+			}
+			projectOwners[*output.ProjectID] = repo.Owner
+		}
+	}
+
+	for projectID, owner := range projectOwners {
+		hash, err := fetchFieldSchemaHash(ctx, ghClient, projectID, owner)
+		if err != nil {
+			return Plan{}, err
+		}
+		plan.Projects = append(plan.Projects, PlanProject{ID: projectID, Owner: owner, FieldSchemaHash: hash})
+	}
+	sort.Slice(plan.Projects, func(i, j int) bool {
+		return plan.Projects[i].ID < plan.Projects[j].ID
+	})
+
+	if plan.ContentHash, err = plan.computeContentHash(); err != nil {
+		return Plan{}, err
+	}
+	if len(signingKey) > 0 {
+		plan.Signature = signPlan(plan.ContentHash, signingKey)
+	}
+
+	return plan, nil
+}
+
+// VerifyPlan checks that the plan is intact and that the config file, templates and project
+// field schemas are unchanged. configFile overrides the config path recorded in the plan.
+// When signingKey is set the plan must carry a valid signature.
+func VerifyPlan(ctx context.Context, plan Plan, configFile string, ghClient GitHubClient, signingKey []byte) error {
+	if plan.Version != PlanVersion {
+		return fmt.Errorf("unsupported plan version %d (expected %d)", plan.Version, PlanVersion)
+	}
+
+	contentHash, err := plan.computeContentHash()
+	if err != nil {
+		return err
+	}
+	if contentHash != plan.ContentHash {
+		return errors.New("plan content hash does not match: the plan file was modified")
+	}
+
+	switch {
+	case len(signingKey) > 0 && plan.Signature == "":
+		return errors.New("plan is not signed")
+	case plan.Signature != "" && len(signingKey) == 0:
+		return errors.New("plan is signed but no signing key was given")
+	case plan.Signature != "" && !hmac.Equal([]byte(plan.Signature), []byte(signPlan(plan.ContentHash, signingKey))):
+		return errors.New("plan signature is invalid")
+	}
+
+	if configFile == "" {
+		configFile = plan.ConfigFile
+	}
+	configHash, err := hashFile(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to hash config file: %w", err)
+	}
+	if configHash != plan.ConfigHash {
+		return fmt.Errorf("%w: config file %s changed", ErrPlanStale, configFile)
+	}
+
+	templates := make([]string, 0, len(plan.TemplateHashes))
+	for path := range plan.TemplateHashes {
+		templates = append(templates, path)
+	}
+	sort.Strings(templates)
+	for _, path := range templates {
+		hash, err := hashFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to hash template file: %w", err)
+		}
+		if hash != plan.TemplateHashes[path] {
+			return fmt.Errorf("%w: template file %s changed", ErrPlanStale, path)
+		}
+	}
+
+	for _, project := range plan.Projects {
+		hash, err := fetchFieldSchemaHash(ctx, ghClient, project.ID, project.Owner)
+		if err != nil {
+			return err
+		}
+		if hash != project.FieldSchemaHash {
+			return fmt.Errorf("%w: fields of project %s changed", ErrPlanStale, project.ID)
+		}
+	}
+
+	return nil
+}
+
+// WritePlan writes the plan as indented JSON.
+func WritePlan(path string, plan Plan) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode plan: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// LoadPlan reads a plan written by WritePlan.
+func LoadPlan(path string) (Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Plan{}, err
+	}
+	var plan Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return Plan{}, fmt.Errorf("failed to parse plan %s: %w", path, err)
+	}
+	return plan, nil
+}
+
+func (p Plan) computeContentHash() (string, error) {
+	p.ContentHash = ""
+	p.Signature = ""
+	data, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode plan: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func signPlan(contentHash string, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(contentHash))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func hashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func fetchFieldSchemaHash(ctx context.Context, ghClient GitHubClient, projectID string, owner string) (string, error) {
+	fields, err := ghClient.GetProjectFields(ctx, projectID, owner)
+	if err != nil {
+		return "", fmt.Errorf("failed to get project fields for %s: %w", projectID, err)
+	}
+	return fieldSchemaHash(fields)
+}
+
+// fieldSchemaHash hashes the IDs, names, types and options of project fields, ignoring their order.
+func fieldSchemaHash(fields []ProjectField) (string, error) {
+	sorted := make([]ProjectField, len(fields))
+	copy(sorted, fields)
+	for i := range sorted {
+		options := make([]ProjectFieldOption, len(sorted[i].Options))
+		copy(options, sorted[i].Options)
+		sort.Slice(options, func(a, b int) bool { return options[a].ID < options[b].ID })
+		sorted[i].Options = options
+		if len(sorted[i].Iterations) > 0 {
+			iterations := make([]ProjectFieldIteration, len(sorted[i].Iterations))
+			copy(iterations, sorted[i].Iterations)
+			sort.Slice(iterations, func(a, b int) bool { return iterations[a].ID < iterations[b].ID })
+			sorted[i].Iterations = iterations
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	data, err := json.Marshal(sorted)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode project fields: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
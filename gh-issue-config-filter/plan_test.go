@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlan(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.yml")
+	templateFile := filepath.Join(dir, "template.md")
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(configFile, "defaults:\n  project_id: PVT_1\n")
+	writeFile(templateFile, "## Tasks\n")
+
+	fields := []ProjectField{
+		{ID: "PVTF_1", Name: "Status", DataType: "SINGLE_SELECT", Options: []ProjectFieldOption{{ID: "OPT_1", Name: "Ready"}}},
+		{ID: "PVTF_2", Name: "Note", DataType: "TEXT"},
+	}
+	outputs := []IssueOutput{
+		{
+			Name:          "Wash My Cat",
+			Title:         "Wash My Cat - 2025-03",
+			TemplateFile:  &templateFile,
+			ProjectID:     stringPtr("PVT_1"),
+			TargetRepo:    stringPtr("owner/repo"),
+			FieldUpdates:  []FieldUpdate{{FieldID: "PVTF_1", FieldType: "SINGLE_SELECT", OptionID: stringPtr("OPT_1")}},
+			OccurrenceKey: "0123abcd",
+			Body:          "## Tasks\n\n" + OccurrenceMarker("0123abcd") + "\n",
+		},
+	}
+	now := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	makePlan := func(t *testing.T, signingKey []byte) Plan {
+		t.Helper()
+		plan, err := NewPlan(ctx, configFile, outputs, newMockGitHubClient(fields), now, signingKey)
+		if err != nil {
+			t.Fatalf("unexpected error making plan: %v", err)
+		}
+		// Round trip through the file, as apply --plan does
+		planFile := filepath.Join(t.TempDir(), "plan.json")
+		if err := WritePlan(planFile, plan); err != nil {
+			t.Fatalf("unexpected error writing plan: %v", err)
+		}
+		loaded, err := LoadPlan(planFile)
+		if err != nil {
+			t.Fatalf("unexpected error loading plan: %v", err)
+		}
+		return loaded
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		plan := makePlan(t, nil)
+		if plan.Version != PlanVersion || len(plan.Issues) != 1 || len(plan.Projects) != 1 || plan.TemplateHashes[templateFile] == "" {
+			t.Errorf("unexpected plan: %+v", plan)
+		}
+		if err := VerifyPlan(ctx, plan, "", newMockGitHubClient(fields), nil); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("field order does not matter", func(t *testing.T) {
+		plan := makePlan(t, nil)
+		reordered := []ProjectField{fields[1], fields[0]}
+		if err := VerifyPlan(ctx, plan, "", newMockGitHubClient(reordered), nil); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("modified plan", func(t *testing.T) {
+		plan := makePlan(t, nil)
+		plan.Issues[0].Title = "Something else"
+		err := VerifyPlan(ctx, plan, "", newMockGitHubClient(fields), nil)
+		if err == nil || !strings.Contains(err.Error(), "content hash") {
+			t.Errorf("expected content hash error, got %v", err)
+		}
+	})
+
+	t.Run("field schema changed", func(t *testing.T) {
+		plan := makePlan(t, nil)
+		changed := []ProjectField{
+			{ID: "PVTF_1", Name: "Status", DataType: "SINGLE_SELECT", Options: []ProjectFieldOption{{ID: "OPT_2", Name: "Ready"}}},
+			fields[1],
+		}
+		err := VerifyPlan(ctx, plan, "", newMockGitHubClient(changed), nil)
+		if !errors.Is(err, ErrPlanStale) {
+			t.Errorf("expected ErrPlanStale, got %v", err)
+		}
+	})
+
+	t.Run("template changed", func(t *testing.T) {
+		plan := makePlan(t, nil)
+		writeFile(templateFile, "## Tasks\n- [ ] new\n")
+		defer writeFile(templateFile, "## Tasks\n")
+		err := VerifyPlan(ctx, plan, "", newMockGitHubClient(fields), nil)
+		if !errors.Is(err, ErrPlanStale) || !strings.Contains(err.Error(), "template") {
+			t.Errorf("expected stale template error, got %v", err)
+		}
+	})
+
+	t.Run("config changed", func(t *testing.T) {
+		plan := makePlan(t, nil)
+		otherConfig := filepath.Join(dir, "other.yml")
+		writeFile(otherConfig, "defaults:\n  project_id: PVT_2\n")
+		err := VerifyPlan(ctx, plan, otherConfig, newMockGitHubClient(fields), nil)
+		if !errors.Is(err, ErrPlanStale) || !strings.Contains(err.Error(), "config") {
+			t.Errorf("expected stale config error, got %v", err)
+		}
+	})
+
+	t.Run("signed", func(t *testing.T) {
+		key := []byte("secret")
+		plan := makePlan(t, key)
+		if plan.Signature == "" {
+			t.Fatal("expected a signature")
+		}
+		if err := VerifyPlan(ctx, plan, "", newMockGitHubClient(fields), key); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := VerifyPlan(ctx, plan, "", newMockGitHubClient(fields), []byte("wrong")); err == nil {
+			t.Error("expected error for a wrong key, got nil")
+		}
+		if err := VerifyPlan(ctx, plan, "", newMockGitHubClient(fields), nil); err == nil {
+			t.Error("expected error without a key, got nil")
+		}
+	})
+
+	t.Run("unsigned plan with a key", func(t *testing.T) {
+		plan := makePlan(t, nil)
+		if err := VerifyPlan(ctx, plan, "", newMockGitHubClient(fields), []byte("secret")); err == nil {
+			t.Error("expected error for an unsigned plan, got nil")
+		}
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		plan := makePlan(t, nil)
+		plan.Version = PlanVersion + 1
+		if err := VerifyPlan(ctx, plan, "", newMockGitHubClient(fields), nil); err == nil {
+			t.Error("expected error for an unsupported version, got nil")
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// runConcurrently calls fn for every index below count, with at most concurrency calls at a time.
+// The error of each call is returned at its index, so results come out in input order whatever
+// order the calls finish in. Once ctx is done, the remaining indices are not started and get ctx's error.
+func runConcurrently(ctx context.Context, concurrency int, count int, fn func(ctx context.Context, i int) error) []error {
+	errs := make([]error, count)
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	concurrency = min(concurrency, count)
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				errs[i] = fn(ctx, i)
+			}
+		}()
+	}
+
+	for i := range count {
+		if ctx.Err() != nil {
+			errs[i] = ctx.Err()
+			continue
+		}
+		select {
+		case indices <- i:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+		}
+	}
+	close(indices)
+	wg.Wait()
+	return errs
+}
+
+// pacer spaces out work shared by several workers, so that it starts at most once per interval
+// however many workers there are.
+type pacer struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newPacer(interval time.Duration) *pacer {
+	return &pacer{interval: interval}
+}
+
+// Wait blocks until the caller's turn, or until ctx is done.
+func (p *pacer) Wait(ctx context.Context) error {
+	if p.interval <= 0 {
+		return ctx.Err()
+	}
+	p.mu.Lock()
+	now := time.Now()
+	if p.next.Before(now) {
+		p.next = now
+	}
+	wait := p.next.Sub(now)
+	p.next = p.next.Add(p.interval)
+	p.mu.Unlock()
+
+	if wait <= 0 {
+		return ctx.Err()
+	}
+	return sleepContext(ctx, wait)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunConcurrently(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+		count       int
+	}{
+		{name: "serial", concurrency: 1, count: 5},
+		{name: "bounded", concurrency: 3, count: 10},
+		{name: "more workers than work", concurrency: 8, count: 2},
+		{name: "no work", concurrency: 4, count: 0},
+		{name: "invalid concurrency", concurrency: 0, count: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			inFlight, maxInFlight := 0, 0
+			errs := runConcurrently(context.Background(), tt.concurrency, tt.count, func(ctx context.Context, i int) error {
+				mu.Lock()
+				inFlight++
+				maxInFlight = max(maxInFlight, inFlight)
+				mu.Unlock()
+				// Later indices finish first
+				time.Sleep(time.Duration(tt.count-i) * time.Millisecond)
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+				return fmt.Errorf("error %d", i)
+			})
+
+			if len(errs) != tt.count {
+				t.Fatalf("expected %d errors, got %d", tt.count, len(errs))
+			}
+			for i, err := range errs {
+				if err == nil || err.Error() != fmt.Sprintf("error %d", i) {
+					t.Errorf("errs[%d]: expected the error of call %d, got %v", i, i, err)
+				}
+			}
+			if maxInFlight > max(tt.concurrency, 1) {
+				t.Errorf("expected at most %d calls at a time, got %d", max(tt.concurrency, 1), maxInFlight)
+			}
+		})
+	}
+}
+
+func TestRunConcurrently_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var mu sync.Mutex
+	var called []int
+	errs := runConcurrently(ctx, 1, 5, func(ctx context.Context, i int) error {
+		mu.Lock()
+		called = append(called, i)
+		mu.Unlock()
+		if i == 1 {
+			cancel()
+		}
+		return nil
+	})
+
+	if len(called) > 3 {
+		t.Errorf("expected the remaining calls not to start after cancellation, got %v", called)
+	}
+	if !errors.Is(errs[4], context.Canceled) {
+		t.Errorf("expected the last call to be canceled, got %v", errs[4])
+	}
+}
+
+func TestPacer(t *testing.T) {
+	p := newPacer(20 * time.Millisecond)
+	start := time.Now()
+	for range 3 {
+		if err := p.Wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// The first call does not wait, the next two wait an interval each
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("expected at least 40ms between three calls, got %s", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := newPacer(time.Hour).Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
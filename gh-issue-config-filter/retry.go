@@ -0,0 +1,348 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RetryOptions configures how requests to GitHub are retried.
+type RetryOptions struct {
+	// MaxAttempts is the number of times a request is sent at most, including the first
+	MaxAttempts int
+	// BaseDelay is the first backoff delay, doubled on every retry up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// MaxWait is the longest a rate limit is waited out; longer waits fail the request instead
+	MaxWait time.Duration
+	// Jitter is the fraction of each delay added at random, so parallel runs do not retry in lockstep
+	Jitter float64
+}
+
+// DefaultRetryOptions returns the retry settings used unless overridden by flags.
+func DefaultRetryOptions() RetryOptions {
+	return RetryOptions{
+		MaxAttempts: 5,
+		BaseDelay:   time.Second,
+		MaxDelay:    time.Minute,
+		MaxWait:     15 * time.Minute,
+		Jitter:      0.2,
+	}
+}
+
+// Validate checks the retry options.
+func (o RetryOptions) Validate() error {
+	if o.MaxAttempts < 1 {
+		return fmt.Errorf("max attempts must be at least 1, got %d", o.MaxAttempts)
+	}
+	if o.Jitter < 0 || o.Jitter > 1 {
+		return fmt.Errorf("jitter must be between 0 and 1, got %g", o.Jitter)
+	}
+	return nil
+}
+
+// secondaryRateLimitDelay is waited after a secondary rate limit response without Retry-After,
+// as GitHub recommends waiting at least one minute.
+const secondaryRateLimitDelay = time.Minute
+
+// retryTransport retries requests that failed because of rate limits, server errors or
+// network errors, with exponential backoff. Requests that change something, such as creating
+// an issue, are only retried on rate limits, as GitHub may have processed them before failing
+// otherwise, and sending them again would repeat the change.
+// The rate limit budget is shared by every request sent through the transport: once one of
+// them is rate limited, the others wait as well instead of using up the limit further.
+type retryTransport struct {
+	next    http.RoundTripper
+	options RetryOptions
+	now     func() time.Time
+	sleep   func(ctx context.Context, d time.Duration) error
+	random  func() float64
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+// retryDecision is whether and when to send a request again.
+type retryDecision struct {
+	retry bool
+	delay time.Duration
+	// rateLimited is set when the delay applies to every request, not only the one that failed
+	rateLimited bool
+	reason      string
+}
+
+func newRetryTransport(next http.RoundTripper, options RetryOptions) *retryTransport {
+	return &retryTransport{
+		next:    next,
+		options: options,
+		now:     time.Now,
+		sleep:   sleepContext,
+		random:  rand.Float64,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	idempotent := isIdempotent(req)
+	for attempt := 1; ; attempt++ {
+		if err := t.waitForPause(req.Context()); err != nil {
+			return nil, err
+		}
+
+		attemptReq := req
+		if attempt > 1 {
+			var err error
+			if attemptReq, err = rewindRequest(req); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := t.next.RoundTrip(attemptReq)
+		if resp != nil {
+			logRateLimit(resp)
+			t.pauseUntilReset(resp)
+		}
+		if req.Context().Err() != nil {
+			return resp, err
+		}
+
+		decision := t.retryDelay(resp, err, attempt, idempotent)
+		if !decision.retry || attempt >= t.options.MaxAttempts || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+		if decision.delay > t.options.MaxWait {
+			Debugf("Not retrying %s %s: %s, would have to wait %s", req.Method, req.URL.Path, decision.reason, decision.delay)
+			return resp, err
+		}
+
+		if resp != nil {
+			// Drain the body so the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		delay := t.addJitter(decision.delay)
+		Debugf("Retrying %s %s in %s (attempt %d of %d): %s", req.Method, req.URL.Path, delay.Round(time.Millisecond), attempt+1, t.options.MaxAttempts, decision.reason)
+		if decision.rateLimited {
+			// Requests sent meanwhile wait in waitForPause
+			t.pause(delay)
+			continue
+		}
+		if err := t.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// pause holds back every request through the transport for d.
+func (t *retryTransport) pause(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if until := t.now().Add(d); until.After(t.pausedUntil) {
+		t.pausedUntil = until
+	}
+}
+
+// pauseUntilReset holds back every request through the transport until the primary rate
+// limit resets when resp used it up, even if resp itself succeeded. Resets further away than
+// MaxWait are not waited for; the requests sent meanwhile fail instead.
+func (t *retryTransport) pauseUntilReset(resp *http.Response) {
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return
+	}
+	reset, ok := rateLimitReset(resp.Header)
+	if !ok {
+		return
+	}
+	// One extra second as the reset time is rounded down
+	if delay := max(reset.Sub(t.now()), 0) + time.Second; delay <= t.options.MaxWait {
+		Debugf("Pausing requests for %s until the rate limit resets", delay)
+		t.pause(delay)
+	}
+}
+
+// paused reports whether requests through the transport are held back by a rate limit.
+func (t *retryTransport) paused() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.pausedUntil.After(t.now())
+}
+
+// waitForPause blocks while the transport is paused by a rate limit.
+func (t *retryTransport) waitForPause(ctx context.Context) error {
+	t.mu.Lock()
+	wait := t.pausedUntil.Sub(t.now())
+	t.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	return t.sleep(ctx, wait)
+}
+
+// retryDelay decides whether a response or error is worth retrying, and after how long.
+// Requests that are not idempotent are only retried when rate limited, which means GitHub
+// did not process them.
+func (t *retryTransport) retryDelay(resp *http.Response, err error, attempt int, idempotent bool) retryDecision {
+	backoff := t.backoff(attempt)
+
+	if err != nil {
+		if !idempotent {
+			return retryDecision{}
+		}
+		return retryDecision{retry: true, delay: backoff, reason: err.Error()}
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests:
+		if delay, ok := retryAfter(resp.Header, t.now()); ok {
+			return retryDecision{retry: true, delay: delay, rateLimited: true, reason: fmt.Sprintf("rate limited (status %d, Retry-After)", resp.StatusCode)}
+		}
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			if reset, ok := rateLimitReset(resp.Header); ok {
+				// One extra second as the reset time is rounded down
+				return retryDecision{retry: true, delay: max(reset.Sub(t.now()), 0) + time.Second, rateLimited: true, reason: "primary rate limit exhausted"}
+			}
+		}
+		if isSecondaryRateLimit(resp) {
+			return retryDecision{retry: true, delay: max(secondaryRateLimitDelay, backoff), rateLimited: true, reason: "secondary rate limit"}
+		}
+		if resp.StatusCode == http.StatusTooManyRequests && idempotent {
+			return retryDecision{retry: true, delay: backoff, rateLimited: true, reason: "too many requests"}
+		}
+		return retryDecision{}
+	case idempotent && (resp.StatusCode == http.StatusBadGateway || resp.StatusCode == http.StatusServiceUnavailable || resp.StatusCode == http.StatusGatewayTimeout):
+		return retryDecision{retry: true, delay: backoff, reason: fmt.Sprintf("server error (status %d)", resp.StatusCode)}
+	default:
+		return retryDecision{}
+	}
+}
+
+// isIdempotent reports whether req can safely be sent again after a server or network error:
+// reads and GraphQL queries can, while other requests and GraphQL mutations may have taken
+// effect already.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		return strings.HasSuffix(req.URL.Path, "/graphql") && isGraphQLQuery(req)
+	default:
+		return false
+	}
+}
+
+// isGraphQLQuery reports whether the body of req is a GraphQL query rather than a mutation.
+func isGraphQLQuery(req *http.Request) bool {
+	if req.GetBody == nil {
+		return false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return false
+	}
+	defer body.Close()
+
+	var payload struct {
+		Query string `json:"query"`
+	}
+	if err := json.NewDecoder(body).Decode(&payload); err != nil {
+		return false
+	}
+	query := strings.TrimSpace(payload.Query)
+	return strings.HasPrefix(query, "query") || strings.HasPrefix(query, "{")
+}
+
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	delay := t.options.BaseDelay << (attempt - 1)
+	if delay > t.options.MaxDelay || delay <= 0 {
+		delay = t.options.MaxDelay
+	}
+	return delay
+}
+
+func (t *retryTransport) addJitter(delay time.Duration) time.Duration {
+	return delay + time.Duration(float64(delay)*t.options.Jitter*t.random())
+}
+
+// retryAfter parses the Retry-After header, given in seconds or as an HTTP date.
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// rateLimitReset parses the X-RateLimit-Reset header, a Unix timestamp in seconds.
+func rateLimitReset(header http.Header) (time.Time, bool) {
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(reset, 0), true
+}
+
+// isSecondaryRateLimit reports whether a 403 or 429 response is GitHub's secondary rate limit,
+// which is only recognizable from its message. The body is left readable.
+func isSecondaryRateLimit(resp *http.Response) bool {
+	if resp.Body == nil {
+		return false
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
+}
+
+// logRateLimit surfaces the remaining rate limit budget in debug logs.
+func logRateLimit(resp *http.Response) {
+	remaining := resp.Header.Get("X-RateLimit-Remaining")
+	if remaining == "" {
+		return
+	}
+	resetAt := ""
+	if reset, ok := rateLimitReset(resp.Header); ok {
+		resetAt = reset.Format(time.RFC3339)
+	}
+	Debugf("Rate limit (%s): %s of %s remaining, resets at %s",
+		resp.Header.Get("X-RateLimit-Resource"), remaining, resp.Header.Get("X-RateLimit-Limit"), resetAt)
+}
+
+// rewindRequest returns a copy of req with a fresh body, to send it again.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %w", err)
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
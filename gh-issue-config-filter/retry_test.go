@@ -0,0 +1,361 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type scriptedResponse struct {
+	status int
+	header map[string]string
+	body   string
+}
+
+func TestRetryTransport(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC)
+	ok := scriptedResponse{status: http.StatusOK, body: `{}`}
+	query := `{"query":"query($id: ID!) { node(id: $id) { id } }"}`
+
+	tests := []struct {
+		name      string
+		responses []scriptedResponse
+		// path and body of the request, a GraphQL query unless set
+		path             string
+		body             string
+		maxAttempts      int
+		expectedStatus   int
+		expectedSleeps   []time.Duration
+		expectedAttempts int
+	}{
+		{
+			name:           "success",
+			responses:      []scriptedResponse{ok},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "server error then success",
+			responses:      []scriptedResponse{{status: http.StatusBadGateway}, ok},
+			expectedStatus: http.StatusOK,
+			expectedSleeps: []time.Duration{time.Second},
+		},
+		{
+			name:           "exponential backoff until max attempts",
+			responses:      []scriptedResponse{{status: http.StatusServiceUnavailable}, {status: http.StatusServiceUnavailable}, {status: http.StatusServiceUnavailable}, ok},
+			maxAttempts:    3,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedSleeps: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:           "Retry-After in seconds",
+			responses:      []scriptedResponse{{status: http.StatusTooManyRequests, header: map[string]string{"Retry-After": "3"}}, ok},
+			expectedStatus: http.StatusOK,
+			expectedSleeps: []time.Duration{3 * time.Second},
+		},
+		{
+			name: "primary rate limit waits until reset",
+			responses: []scriptedResponse{{status: http.StatusForbidden, header: map[string]string{
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     strconv.FormatInt(now.Add(30*time.Second).Unix(), 10),
+			}}, ok},
+			expectedStatus: http.StatusOK,
+			expectedSleeps: []time.Duration{31 * time.Second},
+		},
+		{
+			name:           "secondary rate limit without Retry-After",
+			responses:      []scriptedResponse{{status: http.StatusForbidden, body: `{"message": "You have exceeded a secondary rate limit."}`}, ok},
+			expectedStatus: http.StatusOK,
+			expectedSleeps: []time.Duration{time.Minute},
+		},
+		{
+			name:           "permission error is not retried",
+			responses:      []scriptedResponse{{status: http.StatusForbidden, body: `{"message": "Resource not accessible by integration"}`}, ok},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "not found is not retried",
+			responses:      []scriptedResponse{{status: http.StatusNotFound}, ok},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:             "issue creation is not resent after a server error",
+			responses:        []scriptedResponse{{status: http.StatusBadGateway}, ok},
+			path:             "/repos/owner/repo/issues",
+			body:             `{"title":"x"}`,
+			expectedStatus:   http.StatusBadGateway,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "GraphQL mutation is not resent after a server error",
+			responses:        []scriptedResponse{{status: http.StatusServiceUnavailable}, ok},
+			body:             `{"query":"mutation($input: AddProjectV2ItemByIdInput!) { addProjectV2ItemById(input: $input) { item { id } } }"}`,
+			expectedStatus:   http.StatusServiceUnavailable,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "issue creation is resent after a rate limit",
+			responses:        []scriptedResponse{{status: http.StatusForbidden, header: map[string]string{"Retry-After": "3"}}, ok},
+			path:             "/repos/owner/repo/issues",
+			body:             `{"title":"x"}`,
+			expectedStatus:   http.StatusOK,
+			expectedSleeps:   []time.Duration{3 * time.Second},
+			expectedAttempts: 2,
+		},
+		{
+			name:             "issue creation is not resent after a plain too many requests",
+			responses:        []scriptedResponse{{status: http.StatusTooManyRequests}, ok},
+			path:             "/repos/owner/repo/issues",
+			body:             `{"title":"x"}`,
+			expectedStatus:   http.StatusTooManyRequests,
+			expectedAttempts: 1,
+		},
+		{
+			name:           "wait longer than max wait",
+			responses:      []scriptedResponse{{status: http.StatusTooManyRequests, header: map[string]string{"Retry-After": "3600"}}, ok},
+			expectedStatus: http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var bodies []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(body))
+				resp := tt.responses[len(bodies)-1]
+				for k, v := range resp.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(resp.status)
+				_, _ = io.WriteString(w, resp.body)
+			}))
+			defer server.Close()
+
+			options := DefaultRetryOptions()
+			options.Jitter = 0
+			if tt.maxAttempts != 0 {
+				options.MaxAttempts = tt.maxAttempts
+			}
+			transport := newRetryTransport(http.DefaultTransport, options)
+			transport.now = func() time.Time { return now }
+			var sleeps []time.Duration
+			transport.sleep = func(ctx context.Context, d time.Duration) error {
+				sleeps = append(sleeps, d)
+				return nil
+			}
+
+			path, sent := "/graphql", query
+			if tt.path != "" {
+				path = tt.path
+			}
+			if tt.body != "" {
+				sent = tt.body
+			}
+			req, err := http.NewRequest(http.MethodPost, server.URL+path, strings.NewReader(sent))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := (&http.Client{Transport: transport}).Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if !reflect.DeepEqual(sleeps, tt.expectedSleeps) {
+				t.Errorf("expected sleeps %v, got %v", tt.expectedSleeps, sleeps)
+			}
+			if tt.expectedAttempts != 0 && len(bodies) != tt.expectedAttempts {
+				t.Errorf("expected %d attempts, got %d", tt.expectedAttempts, len(bodies))
+			}
+			for i, body := range bodies {
+				if body != sent {
+					t.Errorf("attempt %d sent body %q, expected the original body", i+1, body)
+				}
+			}
+			if body, _ := io.ReadAll(resp.Body); string(body) != tt.responses[len(bodies)-1].body {
+				t.Errorf("expected the last response body to be readable, got %q", body)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_SharedPause(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "10")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	options := DefaultRetryOptions()
+	options.Jitter = 0
+	transport := newRetryTransport(http.DefaultTransport, options)
+	transport.now = func() time.Time { return now }
+	var sleeps []time.Duration
+	transport.sleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	// Another request sent while the rate limit lasts waits for the rest of it
+	now = now.Add(4 * time.Second)
+	resp, err = client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if expected := []time.Duration{10 * time.Second, 6 * time.Second}; !reflect.DeepEqual(sleeps, expected) {
+		t.Errorf("expected sleeps %v, got %v", expected, sleeps)
+	}
+}
+
+func TestRetryTransport_PausesWhenRateLimitUsedUp(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		resetIn        time.Duration
+		expectedSleeps []time.Duration
+	}{
+		{
+			name:           "reset within max wait",
+			resetIn:        30 * time.Second,
+			expectedSleeps: []time.Duration{31 * time.Second},
+		},
+		{
+			name:    "reset beyond max wait",
+			resetIn: time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Successful, but the last request the rate limit allows
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(tt.resetIn).Unix(), 10))
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			transport := newRetryTransport(http.DefaultTransport, DefaultRetryOptions())
+			transport.now = func() time.Time { return now }
+			var sleeps []time.Duration
+			transport.sleep = func(ctx context.Context, d time.Duration) error {
+				sleeps = append(sleeps, d)
+				return nil
+			}
+			client := &http.Client{Transport: transport}
+
+			for range 2 {
+				resp, err := client.Get(server.URL)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				resp.Body.Close()
+			}
+
+			if !reflect.DeepEqual(sleeps, tt.expectedSleeps) {
+				t.Errorf("expected sleeps %v, got %v", tt.expectedSleeps, sleeps)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_Jitter(t *testing.T) {
+	options := DefaultRetryOptions()
+	options.Jitter = 0.2
+	transport := newRetryTransport(http.DefaultTransport, options)
+	transport.random = func() float64 { return 0.5 }
+	if got := transport.addJitter(10 * time.Second); got != 11*time.Second {
+		t.Errorf("expected 11s, got %s", got)
+	}
+}
+
+func TestRetryTransport_ContextCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	transport := newRetryTransport(http.DefaultTransport, DefaultRetryOptions())
+	transport.sleep = func(ctx context.Context, d time.Duration) error {
+		cancel()
+		return sleepContext(ctx, d)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&http.Client{Transport: transport}).Do(req); err == nil {
+		t.Error("expected error after cancellation, got nil")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value         string
+		expectedDelay time.Duration
+		expectedOK    bool
+	}{
+		{value: "", expectedOK: false},
+		{value: "120", expectedDelay: 2 * time.Minute, expectedOK: true},
+		{value: now.Add(45 * time.Second).Format(http.TimeFormat), expectedDelay: 45 * time.Second, expectedOK: true},
+		{value: now.Add(-time.Minute).Format(http.TimeFormat), expectedDelay: 0, expectedOK: true},
+		{value: "soon", expectedOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			header := http.Header{}
+			if tt.value != "" {
+				header.Set("Retry-After", tt.value)
+			}
+			delay, ok := retryAfter(header, now)
+			if ok != tt.expectedOK || delay != tt.expectedDelay {
+				t.Errorf("expected (%s, %v), got (%s, %v)", tt.expectedDelay, tt.expectedOK, delay, ok)
+			}
+		})
+	}
+}
+
+func TestRetryOptionsValidate(t *testing.T) {
+	if err := DefaultRetryOptions().Validate(); err != nil {
+		t.Errorf("unexpected error for the defaults: %v", err)
+	}
+	invalid := DefaultRetryOptions()
+	invalid.MaxAttempts = 0
+	if err := invalid.Validate(); err == nil {
+		t.Error("expected error for zero max attempts, got nil")
+	}
+	invalid = DefaultRetryOptions()
+	invalid.Jitter = 1.5
+	if err := invalid.Validate(); err == nil {
+		t.Error("expected error for jitter above 1, got nil")
+	}
+}
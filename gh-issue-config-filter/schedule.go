@@ -0,0 +1,167 @@
+package main
+
+import (
+	"slices"
+	"time"
+)
+
+// IsScheduledOn reports whether the issue should be created on the day of t.
+// creation_months and every select months and match any day of them unless day
+// narrows it down to a single day; day alone selects that day in every month.
+// A cron schedule is matched against the day of month, month and weekday of t.
+// Business days in day are taken from calendar; holidays do not shift anything here (see IsDueOn).
+func (i *Issue) IsScheduledOn(t time.Time, calendar Calendar) bool {
+	if i.Schedule != nil {
+		schedule, err := ParseCron(*i.Schedule)
+		if err != nil {
+			Debugf("ignoring issue %s: %v", i.Name, err)
+			return false
+		}
+		return schedule.MatchesDay(t)
+	}
+
+	switch {
+	case i.Every != nil:
+		if !i.Every.Matches(t) {
+			return false
+		}
+	case len(i.CreationMonths) > 0:
+		if !i.IsCreationMonth(Month(t.Month())) {
+			return false
+		}
+	case i.Day == nil:
+		return false
+	}
+
+	if i.Day != nil {
+		day, err := ParseDaySpec(*i.Day)
+		if err != nil {
+			Debugf("ignoring issue %s: %v", i.Name, err)
+			return false
+		}
+		return day.Matches(t, calendar)
+	}
+	return true
+}
+
+// IsDayLevel reports whether the schedule selects individual days rather than whole months.
+func (i *Issue) IsDayLevel() bool {
+	return i.Schedule != nil || i.Day != nil
+}
+
+// PeriodOf returns the period t belongs to: its date ("YYYY-MM-DD") for day-level
+// schedules, or its month ("YYYY-MM") for month-level schedules.
+func (i *Issue) PeriodOf(t time.Time) string {
+	if i.IsDayLevel() {
+		return t.Format("2006-01-02")
+	}
+	return YearMonthOf(t).String()
+}
+
+// PeriodStart returns the start of the period t belongs to, in the location of t.
+func (i *Issue) PeriodStart(t time.Time) time.Time {
+	if i.IsDayLevel() {
+		return startOfDay(t)
+	}
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// IsDueOn reports whether the issue should be created on the day of t once
+// the on_holiday policy is applied to scheduled days that are holidays:
+//   - skip: the occurrence is dropped
+//   - next: the occurrence moves to the following business day
+//   - previous: the occurrence moves to the preceding business day
+//
+// Without a policy, holidays are ignored. Only day-level schedules are shifted: a month-level
+// occurrence is due on the other days of its month anyway, and shifting it could carry it
+// into the neighbouring month, as a second occurrence there.
+func (i *Issue) IsDueOn(t time.Time, calendar Calendar) bool {
+	if i.OnHoliday == nil {
+		return i.IsScheduledOn(t, calendar)
+	}
+
+	if !calendar.IsHoliday(t) && i.IsScheduledOn(t, calendar) {
+		return true
+	}
+	if !i.IsDayLevel() {
+		return false
+	}
+
+	var step int
+	switch *i.OnHoliday {
+	case OnHolidayNext:
+		// Pick up occurrences shifted forward from the holidays right before t
+		step = -1
+	case OnHolidayPrevious:
+		// Pick up occurrences shifted back from the holidays right after t
+		step = 1
+	default:
+		return false
+	}
+
+	if !calendar.IsBusinessDay(t) {
+		return false
+	}
+	for n, day := 0, t.AddDate(0, 0, step); n < maxHolidayShift && !calendar.IsBusinessDay(day); n, day = n+1, day.AddDate(0, 0, step) {
+		if calendar.IsHoliday(day) && i.IsScheduledOn(day, calendar) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActiveOn reports whether t satisfies the year restrictions and the active window of the issue.
+func (i *Issue) IsActiveOn(t time.Time) bool {
+	if len(i.Years) > 0 && !slices.Contains(i.Years, t.Year()) {
+		return false
+	}
+	if i.YearParity != nil {
+		odd := t.Year()%2 != 0
+		if (*i.YearParity == "odd") != odd {
+			return false
+		}
+	}
+
+	day := dateOf(t)
+	if i.ActiveFrom != nil {
+		from, err := ParseDate(*i.ActiveFrom)
+		if err != nil || day.Before(from) {
+			return false
+		}
+	}
+	if i.ActiveUntil != nil {
+		until, err := ParseDate(*i.ActiveUntil)
+		if err != nil || day.After(until) {
+			return false
+		}
+	}
+	return true
+}
+
+// dateOf returns the calendar date of t as midnight UTC, comparable with ParseDate results.
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// DateInMonth returns now if it falls in the given month,
+// otherwise the first day of that month in the year of now.
+func DateInMonth(month Month, now time.Time) time.Time {
+	if Month(now.Month()) == month {
+		return now
+	}
+	return time.Date(now.Year(), time.Month(month), 1, 0, 0, 0, 0, now.Location())
+}
+
+// Matches reports whether t falls in a month selected by the interval.
+// Months before the starting month never match.
+func (iv Interval) Matches(t time.Time) bool {
+	if iv.Months <= 0 {
+		return false
+	}
+	start, err := ParseYearMonth(iv.Starting)
+	if err != nil {
+		return false
+	}
+	elapsed := start.MonthsUntil(YearMonthOf(t))
+	return elapsed >= 0 && elapsed%iv.Months == 0
+}
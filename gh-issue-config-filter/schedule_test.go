@@ -0,0 +1,342 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIssue_IsScheduledOn(t *testing.T) {
+	cases := []struct {
+		name   string
+		issue  Issue
+		day    time.Time
+		expect bool
+	}{
+		{
+			name:   "creation month matches any day",
+			issue:  Issue{CreationMonths: []Month{March}},
+			day:    time.Date(2025, time.March, 20, 0, 0, 0, 0, time.UTC),
+			expect: true,
+		},
+		{
+			name:   "creation month does not match",
+			issue:  Issue{CreationMonths: []Month{March}},
+			day:    time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+		{
+			name:   "cron schedule matches",
+			issue:  Issue{Schedule: stringPtr("0 9 * * MON")},
+			day:    time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC),
+			expect: true,
+		},
+		{
+			name:   "cron schedule does not match",
+			issue:  Issue{Schedule: stringPtr("0 9 * * MON")},
+			day:    time.Date(2025, time.March, 11, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+		{
+			name:   "every 2 months on anchor month",
+			issue:  Issue{Every: &Interval{Months: 2, Starting: "2025-02"}},
+			day:    time.Date(2025, time.February, 10, 0, 0, 0, 0, time.UTC),
+			expect: true,
+		},
+		{
+			name:   "every 2 months across a year boundary",
+			issue:  Issue{Every: &Interval{Months: 2, Starting: "2025-02"}},
+			day:    time.Date(2026, time.February, 10, 0, 0, 0, 0, time.UTC),
+			expect: true,
+		},
+		{
+			name:   "every 2 months off cycle",
+			issue:  Issue{Every: &Interval{Months: 2, Starting: "2025-02"}},
+			day:    time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+		{
+			name:   "every 18 months",
+			issue:  Issue{Every: &Interval{Months: 18, Starting: "2024-01"}},
+			day:    time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC),
+			expect: true,
+		},
+		{
+			name:   "every before anchor",
+			issue:  Issue{Every: &Interval{Months: 1, Starting: "2025-02"}},
+			day:    time.Date(2025, time.January, 10, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+		{
+			name:   "last business day of quarter",
+			issue:  Issue{CreationMonths: []Month{March, June, September, December}, Day: stringPtr("last-business-day")},
+			day:    time.Date(2025, time.June, 30, 0, 0, 0, 0, time.UTC), // Monday
+			expect: true,
+		},
+		{
+			name:   "other day of quarter month",
+			issue:  Issue{CreationMonths: []Month{March, June, September, December}, Day: stringPtr("last-business-day")},
+			day:    time.Date(2025, time.June, 27, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+		{
+			name:   "day alone matches every month",
+			issue:  Issue{Day: stringPtr("2nd-tuesday")},
+			day:    time.Date(2025, time.April, 8, 0, 0, 0, 0, time.UTC),
+			expect: true,
+		},
+		{
+			name:   "day with every",
+			issue:  Issue{Every: &Interval{Months: 2, Starting: "2025-01"}, Day: stringPtr("1st-monday")},
+			day:    time.Date(2025, time.February, 3, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+		{
+			name:   "no schedule never matches",
+			issue:  Issue{},
+			day:    time.Date(2025, time.April, 8, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+		{
+			name:   "malformed cron schedule never matches",
+			issue:  Issue{Schedule: stringPtr("every monday")},
+			day:    time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.issue.IsScheduledOn(tt.day, Calendar{}); got != tt.expect {
+				t.Errorf("expected %v, got %v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestIssue_IsDueOn(t *testing.T) {
+	calendar := NewCalendar(Defaults{
+		Holidays: Holidays{Dates: []string{"2025-03-03", "2025-05-05", "2025-05-06"}}, // Mondays, Tuesday
+		BlackoutPeriods: []BlackoutPeriod{
+			{From: "2025-12-24", Until: "2025-12-31"},
+		},
+	})
+	monday := stringPtr("0 9 * * MON")
+	firstOfMonth := stringPtr("0 9 1 * *")
+	march := []Month{Month(time.March)}
+
+	cases := []struct {
+		name   string
+		issue  Issue
+		day    time.Time
+		expect bool
+	}{
+		{
+			name:   "no policy ignores holidays",
+			issue:  Issue{Schedule: monday},
+			day:    time.Date(2025, time.May, 5, 0, 0, 0, 0, time.UTC),
+			expect: true,
+		},
+		{
+			name:   "skip on holiday",
+			issue:  Issue{Schedule: monday, OnHoliday: stringPtr(OnHolidaySkip)},
+			day:    time.Date(2025, time.May, 5, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+		{
+			name:   "skip does not shift",
+			issue:  Issue{Schedule: monday, OnHoliday: stringPtr(OnHolidaySkip)},
+			day:    time.Date(2025, time.May, 7, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+		{
+			name:   "next moves off the holiday",
+			issue:  Issue{Schedule: monday, OnHoliday: stringPtr(OnHolidayNext)},
+			day:    time.Date(2025, time.May, 5, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+		{
+			name:   "next lands on following business day",
+			issue:  Issue{Schedule: monday, OnHoliday: stringPtr(OnHolidayNext)},
+			day:    time.Date(2025, time.May, 7, 0, 0, 0, 0, time.UTC),
+			expect: true,
+		},
+		{
+			name:   "next skips intermediate holidays",
+			issue:  Issue{Schedule: monday, OnHoliday: stringPtr(OnHolidayNext)},
+			day:    time.Date(2025, time.May, 6, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+		{
+			name:   "previous lands on preceding business day",
+			issue:  Issue{Schedule: monday, OnHoliday: stringPtr(OnHolidayPrevious)},
+			day:    time.Date(2025, time.May, 2, 0, 0, 0, 0, time.UTC), // Friday
+			expect: true,
+		},
+		{
+			name:   "previous does not fire on the weekend",
+			issue:  Issue{Schedule: monday, OnHoliday: stringPtr(OnHolidayPrevious)},
+			day:    time.Date(2025, time.May, 3, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+		{
+			name:   "next shifts out of a blackout period",
+			issue:  Issue{Schedule: stringPtr("0 9 25 12 *"), OnHoliday: stringPtr(OnHolidayNext)},
+			day:    time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), // Thursday
+			expect: true,
+		},
+		{
+			name:   "non-holiday weekend occurrence is kept",
+			issue:  Issue{Schedule: firstOfMonth, OnHoliday: stringPtr(OnHolidayNext)},
+			day:    time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC), // Sunday
+			expect: true,
+		},
+		{
+			name:   "previous does not move a month-level occurrence into the previous month",
+			issue:  Issue{CreationMonths: march, OnHoliday: stringPtr(OnHolidayPrevious)},
+			day:    time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC), // Friday before the 2025-03-03 holiday
+			expect: false,
+		},
+		{
+			name:   "month-level occurrence is due on the other days of its month",
+			issue:  Issue{CreationMonths: march, OnHoliday: stringPtr(OnHolidayPrevious)},
+			day:    time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+			expect: true,
+		},
+		{
+			name:   "month-level occurrence is not due on a holiday",
+			issue:  Issue{CreationMonths: march, OnHoliday: stringPtr(OnHolidayPrevious)},
+			day:    time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.issue.IsDueOn(tt.day, calendar); got != tt.expect {
+				t.Errorf("expected %v, got %v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestIssue_IsActiveOn(t *testing.T) {
+	cases := []struct {
+		name   string
+		issue  Issue
+		day    time.Time
+		expect bool
+	}{
+		{
+			name:   "no restrictions",
+			issue:  Issue{},
+			day:    time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC),
+			expect: true,
+		},
+		{
+			name:   "listed year",
+			issue:  Issue{Years: []int{2025, 2027}},
+			day:    time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC),
+			expect: true,
+		},
+		{
+			name:   "unlisted year",
+			issue:  Issue{Years: []int{2025, 2027}},
+			day:    time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+		{
+			name:   "odd year",
+			issue:  Issue{YearParity: stringPtr("odd")},
+			day:    time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC),
+			expect: true,
+		},
+		{
+			name:   "even year with odd parity",
+			issue:  Issue{YearParity: stringPtr("odd")},
+			day:    time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+		{
+			name:   "even year",
+			issue:  Issue{YearParity: stringPtr("even")},
+			day:    time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC),
+			expect: true,
+		},
+		{
+			name:   "on active_from",
+			issue:  Issue{ActiveFrom: stringPtr("2025-03-10")},
+			day:    time.Date(2025, time.March, 10, 23, 0, 0, 0, time.UTC),
+			expect: true,
+		},
+		{
+			name:   "before active_from",
+			issue:  Issue{ActiveFrom: stringPtr("2025-03-10")},
+			day:    time.Date(2025, time.March, 9, 23, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+		{
+			name:   "on active_until",
+			issue:  Issue{ActiveUntil: stringPtr("2025-03-10")},
+			day:    time.Date(2025, time.March, 10, 23, 0, 0, 0, time.UTC),
+			expect: true,
+		},
+		{
+			name:   "after active_until",
+			issue:  Issue{ActiveUntil: stringPtr("2025-03-10")},
+			day:    time.Date(2025, time.March, 11, 0, 0, 0, 0, time.UTC),
+			expect: false,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.issue.IsActiveOn(tt.day); got != tt.expect {
+				t.Errorf("expected %v, got %v", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestIssue_PeriodOf(t *testing.T) {
+	day := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+
+	monthly := Issue{CreationMonths: []Month{March}}
+	if got := monthly.PeriodOf(day); got != "2025-03" {
+		t.Errorf("expected %q, got %q", "2025-03", got)
+	}
+
+	weekly := Issue{Schedule: stringPtr("0 9 * * MON")}
+	if got := weekly.PeriodOf(day); got != "2025-03-10" {
+		t.Errorf("expected %q, got %q", "2025-03-10", got)
+	}
+
+	relative := Issue{CreationMonths: []Month{March}, Day: stringPtr("2nd-monday")}
+	if got := relative.PeriodOf(day); got != "2025-03-10" {
+		t.Errorf("expected %q, got %q", "2025-03-10", got)
+	}
+}
+
+func TestIssue_PeriodStart(t *testing.T) {
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	now := time.Date(2025, time.March, 10, 9, 30, 0, 0, tokyo)
+
+	monthly := Issue{CreationMonths: []Month{March}}
+	if got, expected := monthly.PeriodStart(now), time.Date(2025, time.March, 1, 0, 0, 0, 0, tokyo); !got.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	weekly := Issue{Schedule: stringPtr("0 9 * * MON")}
+	if got, expected := weekly.PeriodStart(now), time.Date(2025, time.March, 10, 0, 0, 0, 0, tokyo); !got.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestDateInMonth(t *testing.T) {
+	now := time.Date(2025, time.March, 20, 10, 0, 0, 0, time.UTC)
+
+	if got := DateInMonth(March, now); !got.Equal(now) {
+		t.Errorf("expected %v, got %v", now, got)
+	}
+
+	expected := time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)
+	if got := DateInMonth(July, now); !got.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
@@ -3,3 +3,7 @@ package main
 func stringPtr(s string) *string {
 	return &s
 }
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Policies for an occurrence that fails part-way through apply
+const (
+	// OnFailureRollback undoes the steps already taken
+	OnFailureRollback = "rollback"
+	// OnFailureContinue records the failure and leaves the occurrence as it is
+	OnFailureContinue = "continue"
+	// OnFailureResume records the failure and finishes the missing steps on the next run
+	OnFailureResume = "resume"
+)
+
+// How rollback undoes a created issue
+const (
+	RollbackClose  = "close"
+	RollbackDelete = "delete"
+)
+
+// Transaction states
+const (
+	TransactionPending    = "pending"
+	TransactionCompleted  = "completed"
+	TransactionFailed     = "failed"
+	TransactionRolledBack = "rolled_back"
+)
+
+// rolledBackTitlePrefix is prepended to the title of issues closed by rollback.
+const rolledBackTitlePrefix = "[rolled back] "
+
+// Transaction records the steps of apply taken for one occurrence.
+type Transaction struct {
+	Key         string      `json:"key"`
+	Issue       IssueOutput `json:"issue"`
+	Status      string      `json:"status"`
+	IssueNumber int         `json:"issue_number,omitempty"`
+	IssueNodeID string      `json:"issue_node_id,omitempty"`
+	IssueURL    string      `json:"issue_url,omitempty"`
+	ItemID      string      `json:"item_id,omitempty"`
+	// FieldsSet holds the IDs of the fields already set
+	FieldsSet  []string  `json:"fields_set,omitempty"`
+	FailedStep ApplyStep `json:"failed_step,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	// resumed is set on a transaction carried over from an earlier run
+	resumed bool
+}
+
+func newTransaction(output IssueOutput) *Transaction {
+	return &Transaction{
+		Key:    transactionKey(output),
+		Issue:  output,
+		Status: TransactionPending,
+	}
+}
+
+// transactionKey identifies the occurrence of an output, by its occurrence key when present.
+func transactionKey(output IssueOutput) string {
+	if output.OccurrenceKey != "" {
+		return output.OccurrenceKey
+	}
+	repo := ""
+	if output.TargetRepo != nil {
+		repo = *output.TargetRepo
+	}
+	return repo + "\x00" + output.Title
+}
+
+func (t *Transaction) result() ApplyResult {
+	return ApplyResult{Title: t.Issue.Title, IssueURL: t.IssueURL, ItemID: t.ItemID}
+}
+
+func (t *Transaction) fieldSet(fieldID string) bool {
+	for _, id := range t.FieldsSet {
+		if id == fieldID {
+			return true
+		}
+	}
+	return false
+}
+
+// TransactionLog holds the transactions of apply, saved to a state file after every step
+// when it has a path. Transactions applied concurrently are changed through Update only.
+type TransactionLog struct {
+	mu           sync.Mutex
+	path         string
+	Transactions []*Transaction `json:"transactions"`
+}
+
+// LoadTransactionLog reads the state file at path, or starts an empty log if it does not exist.
+// An empty path keeps the log in memory only.
+func LoadTransactionLog(path string) (*TransactionLog, error) {
+	txLog := &TransactionLog{path: path}
+	if path == "" {
+		return txLog, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return txLog, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, txLog); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	return txLog, nil
+}
+
+// Get returns the transaction for an occurrence key, or nil.
+func (l *TransactionLog) Get(key string) *Transaction {
+	for _, tx := range l.Transactions {
+		if tx.Key == key {
+			return tx
+		}
+	}
+	return nil
+}
+
+// Begin starts a new transaction for output, replacing any earlier one for the same occurrence.
+func (l *TransactionLog) Begin(output IssueOutput) *Transaction {
+	tx := newTransaction(output)
+	for i, existing := range l.Transactions {
+		if existing.Key == tx.Key {
+			l.Transactions[i] = tx
+			return tx
+		}
+	}
+	l.Transactions = append(l.Transactions, tx)
+	return tx
+}
+
+// Unfinished returns the transactions that failed part-way and were not rolled back.
+func (l *TransactionLog) Unfinished() []*Transaction {
+	var unfinished []*Transaction
+	for _, tx := range l.Transactions {
+		if tx.Status == TransactionPending || tx.Status == TransactionFailed {
+			unfinished = append(unfinished, tx)
+		}
+	}
+	return unfinished
+}
+
+// Update applies change to a transaction of the log and saves the log.
+func (l *TransactionLog) Update(tx *Transaction, change func(tx *Transaction)) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	change(tx)
+	tx.UpdatedAt = time.Now().UTC()
+	return l.save()
+}
+
+// save writes the log to its state file, if it has one. The caller holds l.mu.
+func (l *TransactionLog) save() error {
+	if l.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+	// Write to a temporary file first so that an interrupted run never leaves a truncated state file
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), filepath.Base(l.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return os.Rename(tmp.Name(), l.path)
+}
+
+// rollbackTransaction removes the project item and closes or deletes the issue created by tx.
+// A closed issue is retitled and loses its occurrence marker, so that the occurrence is not
+// mistaken for an existing issue on the next run.
+func rollbackTransaction(ctx context.Context, ghClient GitHubClient, txLog *TransactionLog, tx *Transaction, mode string) error {
+	var errs []error
+
+	if tx.ItemID != "" {
+		if err := ghClient.DeleteProjectItem(ctx, *tx.Issue.ProjectID, tx.ItemID); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove project item %s: %w", tx.ItemID, err))
+		} else {
+			log.Printf("Rolled back: removed project item %s", tx.ItemID)
+			if err := txLog.Update(tx, func(tx *Transaction) {
+				tx.ItemID = ""
+				tx.FieldsSet = nil
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if tx.IssueNumber != 0 {
+		repo, err := ParseRepo(*tx.Issue.TargetRepo)
+		if err != nil {
+			return err
+		}
+		action := "closed"
+		if mode == RollbackDelete && tx.IssueNodeID != "" {
+			action = "deleted"
+			err = ghClient.DeleteIssue(ctx, tx.IssueNodeID)
+		} else {
+			title := rolledBackTitlePrefix + tx.Issue.Title
+			body := strings.Replace(tx.Issue.Body, OccurrenceMarker(tx.Issue.OccurrenceKey), "", 1)
+			err = ghClient.UpdateIssue(ctx, repo, tx.IssueNumber, IssueUpdate{
+				Title:       &title,
+				Body:        &body,
+				State:       stringPtr("closed"),
+				StateReason: stringPtr("not_planned"),
+			})
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to roll back issue %s: %w", tx.IssueURL, err))
+		} else {
+			log.Printf("Rolled back: %s issue %s", action, tx.IssueURL)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -4,9 +4,56 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 )
 
 func ValidateConfig(config Config, ghClient GitHubClient) error {
+	return ValidateConfigConcurrently(context.Background(), config, ghClient, 1)
+}
+
+// ValidateConfigConcurrently validates the issues concurrency at a time. The error reported is
+// that of the first invalid issue in the config, as with ValidateConfig.
+func ValidateConfigConcurrently(ctx context.Context, config Config, ghClient GitHubClient, concurrency int) error {
+	if err := validateDefaults(config); err != nil {
+		return err
+	}
+
+	// Validate each issue
+	errs := runConcurrently(ctx, concurrency, len(config.Issues), func(ctx context.Context, i int) error {
+		return ValidateIssueWithProject(ctx, config.Issues[i], config.Defaults, ghClient)
+	})
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("issues[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateConfigOffline validates everything that does not need the GitHub API,
+// i.e. all but project fields.
+func ValidateConfigOffline(config Config) error {
+	if err := validateDefaults(config); err != nil {
+		return err
+	}
+
+	for i, issue := range config.Issues {
+		if err := ValidateIssue(issue); err != nil {
+			return fmt.Errorf("issues[%d]: %w", i, err)
+		}
+		if _, err := issue.GetTargetRepo(config.Defaults); err != nil {
+			return fmt.Errorf("issues[%d]: invalid target_repo: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func validateDefaults(config Config) error {
 	if config.Defaults.ProjectID == "" {
 		return errors.New("defaults.project_id is required")
 	}
@@ -16,18 +63,19 @@ func ValidateConfig(config Config, ghClient GitHubClient) error {
 	if len(config.Issues) == 0 {
 		return errors.New("at least one issue is required")
 	}
-
-	// Validate each issue
-	for i, issue := range config.Issues {
-		if err := ValidateIssueWithProject(issue, config.Defaults, ghClient); err != nil {
-			return fmt.Errorf("issues[%d]: %w", i, err)
-		}
+	if err := ValidateCalendar(config.Defaults); err != nil {
+		return err
+	}
+	if _, err := config.Defaults.GetLocation(); err != nil {
+		return fmt.Errorf("defaults.timezone: %w", err)
+	}
+	if err := ValidateMatch(config.Defaults); err != nil {
+		return err
 	}
-
 	return nil
 }
 
-func ValidateIssueWithProject(issue Issue, defaults Defaults, ghClient GitHubClient) error {
+func ValidateIssueWithProject(ctx context.Context, issue Issue, defaults Defaults, ghClient GitHubClient) error {
 	// Basic issue validation
 	if err := ValidateIssue(issue); err != nil {
 		return err
@@ -46,7 +94,6 @@ func ValidateIssueWithProject(issue Issue, defaults Defaults, ghClient GitHubCli
 	}
 
 	// Get project name for error messages
-	ctx := context.Background()
 	projectName, err := ghClient.GetProjectName(ctx, projectID)
 	if err != nil {
 		// Fallback to project ID if name cannot be retrieved
@@ -60,19 +107,12 @@ func ValidateIssueWithProject(issue Issue, defaults Defaults, ghClient GitHubCli
 		Debugf("Successfully retrieved project name: %s for project ID: %s", projectName, projectID)
 	}
 
-	// Get project fields for validation
-	projectFields, err := ghClient.GetProjectFields(ctx, projectID, issueRepo.Owner)
+	// Get project fields for validation, by name for quick lookup
+	fieldMap, err := ProjectFieldMap(ctx, ghClient, projectID, issueRepo.Owner)
 	if err != nil {
 		return fmt.Errorf("failed to get project fields: %w", err)
 	}
 
-	// Create a map of field names to fields for quick lookup
-	fieldMap := make(map[string]ProjectField)
-	for _, field := range projectFields {
-		fieldMap[field.Name] = field
-		Debugf("Found project field: %s (ID: %s, Type: %s)", field.Name, field.ID, field.DataType)
-	}
-
 	// Validate fields
 	// Format project display name: "Name (ID)" if name is different from ID, otherwise just ID
 	projectDisplayName := projectID
@@ -80,7 +120,7 @@ func ValidateIssueWithProject(issue Issue, defaults Defaults, ghClient GitHubCli
 		projectDisplayName = fmt.Sprintf("%s (%s)", projectName, projectID)
 	}
 	Debugf("Using project display name: %s", projectDisplayName)
-	if err := ValidateIssueFields(issue, fieldMap, projectDisplayName); err != nil {
+	if err := ValidateIssueFields(issue, fieldMap, projectDisplayName, defaults.GetMatch()); err != nil {
 		return err
 	}
 
@@ -91,8 +131,20 @@ func ValidateIssue(issue Issue) error {
 	if issue.Name == "" {
 		return errors.New("name is required")
 	}
-	if len(issue.CreationMonths) == 0 {
-		return errors.New("creation_months is required and must not be empty")
+	schedules := 0
+	for _, isSet := range []bool{len(issue.CreationMonths) > 0, issue.Schedule != nil, issue.Every != nil} {
+		if isSet {
+			schedules++
+		}
+	}
+	if schedules == 0 && issue.Day == nil {
+		return errors.New("one of creation_months, schedule, every or day is required")
+	}
+	if schedules > 1 {
+		return errors.New("creation_months, schedule and every are mutually exclusive")
+	}
+	if issue.Schedule != nil && issue.Day != nil {
+		return errors.New("day cannot be combined with schedule")
 	}
 	if issue.TemplateFile == nil {
 		return errors.New("template_file is required")
@@ -104,36 +156,183 @@ func ValidateIssue(issue Issue) error {
 		}
 	}
 
+	if issue.Schedule != nil {
+		if _, err := ParseCron(*issue.Schedule); err != nil {
+			return fmt.Errorf("schedule: %w", err)
+		}
+	}
+
+	if issue.Every != nil {
+		if issue.Every.Months <= 0 {
+			return fmt.Errorf("every.months: invalid value %d (must be at least 1)", issue.Every.Months)
+		}
+		if _, err := ParseYearMonth(issue.Every.Starting); err != nil {
+			return fmt.Errorf("every.starting: %w", err)
+		}
+	}
+
+	if issue.Day != nil {
+		if _, err := ParseDaySpec(*issue.Day); err != nil {
+			return fmt.Errorf("day: %w", err)
+		}
+	}
+
+	if issue.Timezone != nil {
+		if _, err := time.LoadLocation(*issue.Timezone); err != nil {
+			return fmt.Errorf("timezone: %w", err)
+		}
+	}
+
+	if issue.OnHoliday != nil {
+		switch *issue.OnHoliday {
+		case OnHolidaySkip, OnHolidayNext, OnHolidayPrevious:
+		default:
+			return fmt.Errorf("on_holiday: invalid value '%s' (must be 'skip', 'next' or 'previous')", *issue.OnHoliday)
+		}
+	}
+
+	if err := validateActivePeriod(issue); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ValidateCalendar checks the holidays and blackout periods in defaults.
+func ValidateCalendar(defaults Defaults) error {
+	for i, dateStr := range defaults.Holidays.Dates {
+		if _, err := ParseDate(dateStr); err != nil {
+			return fmt.Errorf("defaults.holidays.dates[%d]: %w", i, err)
+		}
+	}
+	for i, period := range defaults.BlackoutPeriods {
+		from, err := ParseDate(period.From)
+		if err != nil {
+			return fmt.Errorf("defaults.blackout_periods[%d].from: %w", i, err)
+		}
+		until, err := ParseDate(period.Until)
+		if err != nil {
+			return fmt.Errorf("defaults.blackout_periods[%d].until: %w", i, err)
+		}
+		if until.Before(from) {
+			return fmt.Errorf("defaults.blackout_periods[%d]: until (%s) must not be before from (%s)", i, period.Until, period.From)
+		}
+	}
+	return nil
+}
+
+func validateActivePeriod(issue Issue) error {
+	if issue.YearParity != nil && *issue.YearParity != "odd" && *issue.YearParity != "even" {
+		return fmt.Errorf("year_parity: invalid value '%s' (must be 'odd' or 'even')", *issue.YearParity)
+	}
+
+	for i, year := range issue.Years {
+		if year <= 0 {
+			return fmt.Errorf("years[%d]: invalid year %d", i, year)
+		}
+		if issue.YearParity != nil && (*issue.YearParity == "odd") != (year%2 != 0) {
+			return fmt.Errorf("years[%d]: %d is not an %s year", i, year, *issue.YearParity)
+		}
+	}
+
+	var from, until time.Time
+	var err error
+	if issue.ActiveFrom != nil {
+		if from, err = ParseDate(*issue.ActiveFrom); err != nil {
+			return fmt.Errorf("active_from: %w", err)
+		}
+	}
+	if issue.ActiveUntil != nil {
+		if until, err = ParseDate(*issue.ActiveUntil); err != nil {
+			return fmt.Errorf("active_until: %w", err)
+		}
+	}
+	if issue.ActiveFrom != nil && issue.ActiveUntil != nil && until.Before(from) {
+		return fmt.Errorf("active_until (%s) must not be before active_from (%s)", *issue.ActiveUntil, *issue.ActiveFrom)
+	}
+
+	if len(issue.Years) > 0 && (issue.ActiveFrom != nil || issue.ActiveUntil != nil) {
+		inWindow := false
+		for _, year := range issue.Years {
+			if (issue.ActiveFrom == nil || year >= from.Year()) && (issue.ActiveUntil == nil || year <= until.Year()) {
+				inWindow = true
+				break
+			}
+		}
+		if !inWindow {
+			return errors.New("years: none of the listed years overlaps active_from/active_until")
+		}
+	}
+
 	return nil
 }
 
-func ValidateIssueFields(issue Issue, fieldMap map[string]ProjectField, projectName string) error {
+func ValidateIssueFields(issue Issue, fieldMap map[string]ProjectField, projectName string, match string) error {
 	for fieldName, fieldValue := range issue.Fields {
 		Debugf("Validating field '%s' with value '%s'", fieldName, fieldValue)
-		field, exists := fieldMap[fieldName]
-		if !exists {
-			// Get list of available field names
-			availableFields := make([]string, 0, len(fieldMap))
-			for name := range fieldMap {
-				availableFields = append(availableFields, name)
+		field, err := LookupField(fieldMap, fieldName, match, projectName)
+		if err != nil {
+			return err
+		}
+
+		// For date fields, validate the date or template
+		if field.DataType == "DATE" {
+			if err := ValidateDateField(fieldValue); err != nil {
+				return fmt.Errorf("field '%s' in project '%s': %w", fieldName, projectName, err)
 			}
-			return fmt.Errorf("field '%s' does not exist in project '%s'. Available fields: %v", fieldName, projectName, availableFields)
+			continue
 		}
 
-		// For single-select fields, validate that the option exists
-		if field.DataType == "SINGLE_SELECT" {
-			optionExists := false
-			for _, option := range field.Options {
-				if option.Name == fieldValue {
-					optionExists = true
-					break
-				}
+		if !strings.Contains(fieldValue, "{{") {
+			if err := validateFieldValue(fieldName, field, fieldValue, projectName, match); err != nil {
+				return err
 			}
-			if !optionExists {
-				return fmt.Errorf("field '%s': option '%s' does not exist", fieldName, fieldValue)
+			continue
+		}
+
+		// Templated values may depend on the month or quarter, so check them as rendered in every month
+		for month := time.January; month <= time.December; month++ {
+			date := time.Date(dateFieldReference.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+			value, err := expandFieldValue(fieldValue, date)
+			if err != nil {
+				return fmt.Errorf("field '%s' in project '%s': %w", fieldName, projectName, err)
+			}
+			if err := validateFieldValue(fieldName, field, value, projectName, match); err != nil {
+				return fmt.Errorf("%w (rendered from '%s' for %s)", err, fieldValue, date.Format("2006-01"))
 			}
 		}
 	}
 
 	return nil
 }
+
+// validateFieldValue checks a rendered value against the type of its field.
+func validateFieldValue(fieldName string, field ProjectField, value string, projectName string, match string) error {
+	switch field.DataType {
+	case "SINGLE_SELECT":
+		// Validate that the option exists
+		if _, err := LookupOption(field, value, match); err != nil {
+			return err
+		}
+	case "NUMBER":
+		// Validate that the value is a number
+		if _, err := ParseNumberField(value); err != nil {
+			return fmt.Errorf("field '%s' in project '%s': %w", fieldName, projectName, err)
+		}
+	case "ITERATION":
+		// Validate the relative iteration or title
+		if err := ValidateIterationValue(field, value); err != nil {
+			return fmt.Errorf("field '%s': %w", fieldName, err)
+		}
+	}
+	return nil
+}
+
+// ParseNumberField parses the value of a NUMBER field, which GitHub stores as a float.
+func ParseNumberField(value string) (float64, error) {
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, fmt.Errorf("value '%s' is not a number, as required by NUMBER fields", value)
+	}
+	return number, nil
+}
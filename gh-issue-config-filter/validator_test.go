@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestValidateConfig(t *testing.T) {
@@ -57,6 +60,26 @@ func TestValidateConfig(t *testing.T) {
 			expectError:         true,
 			expectErrorContains: "defaults.target_repo is required",
 		},
+		{
+			name: "invalid - unknown default timezone",
+			config: Config{
+				Defaults: Defaults{
+					ProjectID:  "default_project_id",
+					TargetRepo: "default/repo",
+					Timezone:   stringPtr("Mars/Olympus_Mons"),
+				},
+				Issues: []Issue{
+					{
+						Name:           "test",
+						CreationMonths: []Month{January},
+						TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+					},
+				},
+			},
+			mockFields:          []ProjectField{},
+			expectError:         true,
+			expectErrorContains: "defaults.timezone: unknown time zone",
+		},
 		{
 			name: "invalid - no issues",
 			config: Config{
@@ -139,6 +162,321 @@ func TestValidateConfig(t *testing.T) {
 			expectError:         true,
 			expectErrorContains: "field 'Status': option 'InvalidOption' does not exist",
 		},
+		{
+			name: "invalid - misspelt option suggests the closest one",
+			config: Config{
+				Defaults: Defaults{
+					ProjectID:  "default_project_id",
+					TargetRepo: "default/repo",
+				},
+				Issues: []Issue{
+					{
+						Name:           "test",
+						CreationMonths: []Month{January},
+						TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+						Fields: map[string]string{
+							"Status": "Redy",
+						},
+					},
+				},
+			},
+			mockFields: []ProjectField{
+				{
+					ID:       "PVTFL_1",
+					Name:     "Status",
+					DataType: "SINGLE_SELECT",
+					Options: []ProjectFieldOption{
+						{ID: "OPT_1", Name: "Ready"},
+						{ID: "OPT_2", Name: "In Progress"},
+					},
+				},
+			},
+			expectError:         true,
+			expectErrorContains: "field 'Status': option 'Redy' does not exist; did you mean 'Ready'? Available options: ['In Progress', 'Ready']",
+		},
+		{
+			name: "valid - case-insensitive field and option names",
+			config: Config{
+				Defaults: Defaults{
+					ProjectID:  "default_project_id",
+					TargetRepo: "default/repo",
+					Match:      stringPtr(MatchCaseInsensitive),
+				},
+				Issues: []Issue{
+					{
+						Name:           "test",
+						CreationMonths: []Month{January},
+						TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+						Fields: map[string]string{
+							"status": "in progress",
+						},
+					},
+				},
+			},
+			mockFields: []ProjectField{
+				{
+					ID:       "PVTFL_1",
+					Name:     "Status",
+					DataType: "SINGLE_SELECT",
+					Options: []ProjectFieldOption{
+						{ID: "OPT_1", Name: "Ready"},
+						{ID: "OPT_2", Name: "In Progress"},
+					},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid - field names differ in case without case-insensitive matching",
+			config: Config{
+				Defaults: Defaults{
+					ProjectID:  "default_project_id",
+					TargetRepo: "default/repo",
+				},
+				Issues: []Issue{
+					{
+						Name:           "test",
+						CreationMonths: []Month{January},
+						TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+						Fields: map[string]string{
+							"status": "Ready",
+						},
+					},
+				},
+			},
+			mockFields: []ProjectField{
+				{
+					ID:       "PVTFL_1",
+					Name:     "Status",
+					DataType: "SINGLE_SELECT",
+					Options: []ProjectFieldOption{
+						{ID: "OPT_1", Name: "Ready"},
+					},
+				},
+			},
+			expectError:         true,
+			expectErrorContains: "field 'status' does not exist in project 'Project default_project_id (default_project_id)'; did you mean 'Status'?",
+		},
+		{
+			name: "valid - templated field values",
+			config: Config{
+				Defaults: Defaults{
+					ProjectID:  "default_project_id",
+					TargetRepo: "default/repo",
+				},
+				Issues: []Issue{
+					{
+						Name:           "test",
+						CreationMonths: []Month{January},
+						TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+						Fields: map[string]string{
+							"Period":   "{{YearMonth}}",
+							"Estimate": `{{if eq Quarter "4"}}8{{else}}3{{end}}`,
+						},
+					},
+				},
+			},
+			mockFields: []ProjectField{
+				{ID: "PVTF_1", Name: "Period", DataType: "TEXT"},
+				{ID: "PVTF_2", Name: "Estimate", DataType: "NUMBER"},
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid - field value template does not parse",
+			config: Config{
+				Defaults: Defaults{
+					ProjectID:  "default_project_id",
+					TargetRepo: "default/repo",
+				},
+				Issues: []Issue{
+					{
+						Name:           "test",
+						CreationMonths: []Month{January},
+						TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+						Fields: map[string]string{
+							"Period": "{{FiscalYear}}",
+						},
+					},
+				},
+			},
+			mockFields: []ProjectField{
+				{ID: "PVTF_1", Name: "Period", DataType: "TEXT"},
+			},
+			expectError:         true,
+			expectErrorContains: "field 'Period' in project 'Project default_project_id (default_project_id)': failed to parse field value template",
+		},
+		{
+			name: "invalid - templated option does not exist in some months",
+			config: Config{
+				Defaults: Defaults{
+					ProjectID:  "default_project_id",
+					TargetRepo: "default/repo",
+				},
+				Issues: []Issue{
+					{
+						Name:           "test",
+						CreationMonths: []Month{January},
+						TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+						Fields: map[string]string{
+							"Stage": "Q{{Quarter}}",
+						},
+					},
+				},
+			},
+			mockFields: []ProjectField{
+				{
+					ID:       "PVTF_1",
+					Name:     "Stage",
+					DataType: "SINGLE_SELECT",
+					Options: []ProjectFieldOption{
+						{ID: "OPT_1", Name: "Q1"},
+						{ID: "OPT_2", Name: "Q2"},
+					},
+				},
+			},
+			expectError:         true,
+			expectErrorContains: "option 'Q3' does not exist",
+		},
+		{
+			name: "invalid - unknown match mode",
+			config: Config{
+				Defaults: Defaults{
+					ProjectID:  "default_project_id",
+					TargetRepo: "default/repo",
+					Match:      stringPtr("fuzzy"),
+				},
+				Issues: []Issue{
+					{
+						Name:           "test",
+						CreationMonths: []Month{January},
+						TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+					},
+				},
+			},
+			expectError:         true,
+			expectErrorContains: "defaults.match: invalid value 'fuzzy'",
+		},
+		{
+			name: "invalid - number field value is not a number",
+			config: Config{
+				Defaults: Defaults{
+					ProjectID:  "default_project_id",
+					TargetRepo: "default/repo",
+				},
+				Issues: []Issue{
+					{
+						Name:           "test",
+						CreationMonths: []Month{January},
+						TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+						Fields: map[string]string{
+							"SP": "five",
+						},
+					},
+				},
+			},
+			mockFields: []ProjectField{
+				{ID: "PVTFL_1", Name: "SP", DataType: "NUMBER"},
+			},
+			expectError:         true,
+			expectErrorContains: "field 'SP' in project 'Project default_project_id (default_project_id)': value 'five' is not a number",
+		},
+		{
+			name: "valid - number field",
+			config: Config{
+				Defaults: Defaults{
+					ProjectID:  "default_project_id",
+					TargetRepo: "default/repo",
+				},
+				Issues: []Issue{
+					{
+						Name:           "test",
+						CreationMonths: []Month{January},
+						TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+						Fields: map[string]string{
+							"SP": " 2.5 ",
+						},
+					},
+				},
+			},
+			mockFields: []ProjectField{
+				{ID: "PVTFL_1", Name: "SP", DataType: "NUMBER"},
+			},
+			expectError: false,
+		},
+		{
+			name: "valid - date fields",
+			config: Config{
+				Defaults: Defaults{
+					ProjectID:  "default_project_id",
+					TargetRepo: "default/repo",
+				},
+				Issues: []Issue{
+					{
+						Name:           "test",
+						CreationMonths: []Month{January},
+						TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+						Fields: map[string]string{
+							"Start date": "2025-01-06",
+							"Due date":   "{{AddDays 14}}",
+						},
+					},
+				},
+			},
+			mockFields: []ProjectField{
+				{ID: "PVTFL_1", Name: "Start date", DataType: "DATE"},
+				{ID: "PVTFL_2", Name: "Due date", DataType: "DATE"},
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid - date field template",
+			config: Config{
+				Defaults: Defaults{
+					ProjectID:  "default_project_id",
+					TargetRepo: "default/repo",
+				},
+				Issues: []Issue{
+					{
+						Name:           "test",
+						CreationMonths: []Month{January},
+						TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+						Fields: map[string]string{
+							"Due date": "{{AddDays}}",
+						},
+					},
+				},
+			},
+			mockFields: []ProjectField{
+				{ID: "PVTFL_2", Name: "Due date", DataType: "DATE"},
+			},
+			expectError:         true,
+			expectErrorContains: "field 'Due date' in project 'Project default_project_id (default_project_id)': failed to execute date template",
+		},
+		{
+			name: "invalid - iteration does not exist",
+			config: Config{
+				Defaults: Defaults{
+					ProjectID:  "default_project_id",
+					TargetRepo: "default/repo",
+				},
+				Issues: []Issue{
+					{
+						Name:           "test",
+						CreationMonths: []Month{January},
+						TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+						Fields: map[string]string{
+							"Sprint": "Sprint 9",
+						},
+					},
+				},
+			},
+			mockFields: []ProjectField{
+				{ID: "PVTIF_1", Name: "Sprint", DataType: "ITERATION", Iterations: []ProjectFieldIteration{{ID: "IT_1", Title: "Sprint 1"}}},
+			},
+			expectError:         true,
+			expectErrorContains: "field 'Sprint': iteration 'Sprint 9' does not exist",
+		},
 		{
 			name: "valid - field exists",
 			config: Config{
@@ -280,9 +618,140 @@ func TestValidateConfig(t *testing.T) {
 	}
 }
 
+func TestParseNumberField(t *testing.T) {
+	tests := []struct {
+		value       string
+		expected    float64
+		expectError bool
+	}{
+		{value: "5", expected: 5},
+		{value: "0.5", expected: 0.5},
+		{value: " -3 ", expected: -3},
+		{value: "1e3", expected: 1000},
+		{value: "five", expectError: true},
+		{value: "", expectError: true},
+		{value: "NaN", expectError: true},
+		{value: "Inf", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			number, err := ParseNumberField(tt.value)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error, got %v", number)
+				}
+				return
+			}
+			if err != nil || number != tt.expected {
+				t.Errorf("expected %v, got %v (error %v)", tt.expected, number, err)
+			}
+		})
+	}
+}
+
+func TestValidateConfigConcurrently(t *testing.T) {
+	fields := []ProjectField{{ID: "PVTFL_1", Name: "Status", DataType: "TEXT"}}
+	config := Config{Defaults: Defaults{ProjectID: "default_project_id", TargetRepo: "default/repo"}}
+	for i := range 8 {
+		issue := Issue{
+			Name:           fmt.Sprintf("issue %d", i),
+			CreationMonths: []Month{January},
+			TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+			Fields:         map[string]string{"Status": "Ready"},
+		}
+		if i == 3 || i == 6 {
+			issue.Fields = map[string]string{"Missing": "value"}
+		}
+		config.Issues = append(config.Issues, issue)
+	}
+
+	// The first invalid issue is reported, however many run at a time
+	for _, concurrency := range []int{1, 4} {
+		err := ValidateConfigConcurrently(context.Background(), config, newMockGitHubClient(fields), concurrency)
+		if err == nil || !contains(err.Error(), "issues[3]:") {
+			t.Errorf("concurrency %d: expected error for issues[3], got %v", concurrency, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := ValidateConfigConcurrently(ctx, config, newMockGitHubClient(fields), 4); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// blockingGitHubClient holds project field requests until their context is done.
+type blockingGitHubClient struct {
+	GitHubClient
+	started chan struct{}
+}
+
+func (c *blockingGitHubClient) GetProjectFields(ctx context.Context, projectID string, owner string) ([]ProjectField, error) {
+	c.started <- struct{}{}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestValidateConfigConcurrently_CancelsInFlightRequests(t *testing.T) {
+	config := Config{Defaults: Defaults{ProjectID: "default_project_id", TargetRepo: "default/repo"}}
+	for i := range 4 {
+		config.Issues = append(config.Issues, Issue{
+			Name:           fmt.Sprintf("issue %d", i),
+			CreationMonths: []Month{January},
+			TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+		})
+	}
+	client := &blockingGitHubClient{GitHubClient: newMockGitHubClient(nil), started: make(chan struct{}, len(config.Issues))}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-client.started
+		cancel()
+	}()
+	done := make(chan error, 1)
+	go func() { done <- ValidateConfigConcurrently(ctx, config, client, 2) }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("requests in flight were not cancelled")
+	}
+}
+
+func TestValidateConfigOffline(t *testing.T) {
+	valid := Config{
+		Defaults: Defaults{ProjectID: "default_project_id", TargetRepo: "default/repo"},
+		Issues: []Issue{
+			{
+				Name:           "test",
+				CreationMonths: []Month{January},
+				TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+				Fields:         map[string]string{"NonExistentField": "value"},
+			},
+		},
+	}
+	if err := ValidateConfigOffline(valid); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	invalidRepo := valid
+	invalidRepo.Issues = []Issue{valid.Issues[0]}
+	invalidRepo.Issues[0].TargetRepo = stringPtr("not-a-repo")
+	if err := ValidateConfigOffline(invalidRepo); err == nil || !contains(err.Error(), "issues[0]: invalid target_repo") {
+		t.Errorf("expected invalid target_repo error, got %v", err)
+	}
+}
+
 // mockGitHubClient is a mock implementation of GitHubClient for testing
 type mockGitHubClient struct {
 	fieldsByProject map[string][]ProjectField
+	// existingIssues holds the issues returned by SearchIssues, keyed by "owner/repo"
+	existingIssues map[string][]ExistingIssue
+	searchQueries  []string
 }
 
 func (m *mockGitHubClient) GetProjectFields(ctx context.Context, projectID string, owner string) ([]ProjectField, error) {
@@ -302,6 +771,44 @@ func (m *mockGitHubClient) GetProjectName(ctx context.Context, projectID string)
 	return fmt.Sprintf("Project %s", projectID), nil
 }
 
+func (m *mockGitHubClient) SearchIssues(ctx context.Context, query string) ([]ExistingIssue, error) {
+	m.searchQueries = append(m.searchQueries, query)
+	for _, qualifier := range strings.Fields(query) {
+		if repo, ok := strings.CutPrefix(qualifier, "repo:"); ok {
+			return m.existingIssues[repo], nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockGitHubClient) CreateIssue(ctx context.Context, repo Repo, title string, body string) (CreatedIssue, error) {
+	return CreatedIssue{}, errors.New("CreateIssue is not supported by the mock")
+}
+
+func (m *mockGitHubClient) GetIssueNodeID(ctx context.Context, repo Repo, number int) (string, error) {
+	return "", errors.New("GetIssueNodeID is not supported by the mock")
+}
+
+func (m *mockGitHubClient) AddProjectItem(ctx context.Context, projectID string, contentID string) (string, error) {
+	return "", errors.New("AddProjectItem is not supported by the mock")
+}
+
+func (m *mockGitHubClient) UpdateProjectItemField(ctx context.Context, projectID string, itemID string, update FieldUpdate) error {
+	return errors.New("UpdateProjectItemField is not supported by the mock")
+}
+
+func (m *mockGitHubClient) UpdateIssue(ctx context.Context, repo Repo, number int, update IssueUpdate) error {
+	return errors.New("UpdateIssue is not supported by the mock")
+}
+
+func (m *mockGitHubClient) DeleteIssue(ctx context.Context, issueID string) error {
+	return errors.New("DeleteIssue is not supported by the mock")
+}
+
+func (m *mockGitHubClient) DeleteProjectItem(ctx context.Context, projectID string, itemID string) error {
+	return errors.New("DeleteProjectItem is not supported by the mock")
+}
+
 // newMockGitHubClient creates a mock GitHub client with fields for a single project
 func newMockGitHubClient(fields []ProjectField) *mockGitHubClient {
 	return &mockGitHubClient{
@@ -331,6 +838,51 @@ func indexOfSubstring(s, substr string) int {
 	return -1
 }
 
+func TestValidateCalendar(t *testing.T) {
+	cases := []struct {
+		name                string
+		defaults            Defaults
+		expectErrorContains string
+	}{
+		{
+			name: "valid",
+			defaults: Defaults{
+				Holidays:        Holidays{Dates: []string{"2025-01-01"}},
+				BlackoutPeriods: []BlackoutPeriod{{From: "2025-12-25", Until: "2026-01-05"}},
+			},
+		},
+		{
+			name:                "invalid holiday date",
+			defaults:            Defaults{Holidays: Holidays{Dates: []string{"2025-13-01"}}},
+			expectErrorContains: "defaults.holidays.dates[0]: invalid date format",
+		},
+		{
+			name:                "invalid blackout from",
+			defaults:            Defaults{BlackoutPeriods: []BlackoutPeriod{{From: "soon", Until: "2026-01-05"}}},
+			expectErrorContains: "defaults.blackout_periods[0].from",
+		},
+		{
+			name:                "blackout ends before it starts",
+			defaults:            Defaults{BlackoutPeriods: []BlackoutPeriod{{From: "2026-01-05", Until: "2025-12-25"}}},
+			expectErrorContains: "until (2025-12-25) must not be before from (2026-01-05)",
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCalendar(tt.defaults)
+			if tt.expectErrorContains == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !contains(err.Error(), tt.expectErrorContains) {
+				t.Errorf("expected error containing %q, got %v", tt.expectErrorContains, err)
+			}
+		})
+	}
+}
+
 func TestValidateIssue(t *testing.T) {
 	cases := []struct {
 		name                string
@@ -365,7 +917,200 @@ func TestValidateIssue(t *testing.T) {
 				TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
 			},
 			expectError:         true,
-			expectErrorContains: "creation_months is required and must not be empty",
+			expectErrorContains: "one of creation_months, schedule, every or day is required",
+		},
+		{
+			name: "valid - cron schedule",
+			issue: Issue{
+				Name:         "test",
+				Schedule:     stringPtr("0 9 1,15 * *"),
+				TemplateFile: stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid - malformed cron schedule",
+			issue: Issue{
+				Name:         "test",
+				Schedule:     stringPtr("0 9 * *"),
+				TemplateFile: stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+			},
+			expectError:         true,
+			expectErrorContains: "schedule: invalid cron expression",
+		},
+		{
+			name: "invalid - both creation_months and schedule",
+			issue: Issue{
+				Name:           "test",
+				CreationMonths: []Month{January},
+				Schedule:       stringPtr("0 9 * * MON"),
+				TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+			},
+			expectError:         true,
+			expectErrorContains: "creation_months, schedule and every are mutually exclusive",
+		},
+		{
+			name: "valid - every interval",
+			issue: Issue{
+				Name:         "test",
+				Every:        &Interval{Months: 2, Starting: "2025-02"},
+				TemplateFile: stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid - every without months",
+			issue: Issue{
+				Name:         "test",
+				Every:        &Interval{Starting: "2025-02"},
+				TemplateFile: stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+			},
+			expectError:         true,
+			expectErrorContains: "every.months: invalid value 0",
+		},
+		{
+			name: "invalid - every with malformed anchor",
+			issue: Issue{
+				Name:         "test",
+				Every:        &Interval{Months: 2, Starting: "February 2025"},
+				TemplateFile: stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+			},
+			expectError:         true,
+			expectErrorContains: "every.starting: invalid year-month format",
+		},
+		{
+			name: "valid - day alone",
+			issue: Issue{
+				Name:         "test",
+				Day:          stringPtr("2nd-tuesday"),
+				TemplateFile: stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid - malformed day",
+			issue: Issue{
+				Name:           "test",
+				CreationMonths: []Month{March},
+				Day:            stringPtr("second tuesday"),
+				TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+			},
+			expectError:         true,
+			expectErrorContains: "day: invalid day",
+		},
+		{
+			name: "invalid - day with cron schedule",
+			issue: Issue{
+				Name:         "test",
+				Schedule:     stringPtr("0 9 * * MON"),
+				Day:          stringPtr("last-business-day"),
+				TemplateFile: stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+			},
+			expectError:         true,
+			expectErrorContains: "day cannot be combined with schedule",
+		},
+		{
+			name: "invalid - unknown timezone",
+			issue: Issue{
+				Name:           "test",
+				CreationMonths: []Month{January},
+				Timezone:       stringPtr("Mars/Olympus_Mons"),
+				TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+			},
+			expectError:         true,
+			expectErrorContains: "timezone: unknown time zone",
+		},
+		{
+			name: "valid - holiday policy",
+			issue: Issue{
+				Name:         "test",
+				Schedule:     stringPtr("0 9 * * MON"),
+				OnHoliday:    stringPtr("next"),
+				TemplateFile: stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid - unknown holiday policy",
+			issue: Issue{
+				Name:         "test",
+				Schedule:     stringPtr("0 9 * * MON"),
+				OnHoliday:    stringPtr("postpone"),
+				TemplateFile: stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+			},
+			expectError:         true,
+			expectErrorContains: "on_holiday: invalid value 'postpone'",
+		},
+		{
+			name: "valid - biennial with window",
+			issue: Issue{
+				Name:           "test",
+				CreationMonths: []Month{April},
+				YearParity:     stringPtr("odd"),
+				Years:          []int{2025, 2027},
+				ActiveFrom:     stringPtr("2025-01-01"),
+				ActiveUntil:    stringPtr("2027-12-31"),
+				TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid - unknown year parity",
+			issue: Issue{
+				Name:           "test",
+				CreationMonths: []Month{April},
+				YearParity:     stringPtr("leap"),
+				TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+			},
+			expectError:         true,
+			expectErrorContains: "year_parity: invalid value 'leap'",
+		},
+		{
+			name: "invalid - year contradicts parity",
+			issue: Issue{
+				Name:           "test",
+				CreationMonths: []Month{April},
+				YearParity:     stringPtr("even"),
+				Years:          []int{2025},
+				TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+			},
+			expectError:         true,
+			expectErrorContains: "years[0]: 2025 is not an even year",
+		},
+		{
+			name: "invalid - malformed active_from",
+			issue: Issue{
+				Name:           "test",
+				CreationMonths: []Month{April},
+				ActiveFrom:     stringPtr("2025/01/01"),
+				TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+			},
+			expectError:         true,
+			expectErrorContains: "active_from: invalid date format",
+		},
+		{
+			name: "invalid - window ends before it starts",
+			issue: Issue{
+				Name:           "test",
+				CreationMonths: []Month{April},
+				ActiveFrom:     stringPtr("2026-01-01"),
+				ActiveUntil:    stringPtr("2025-12-31"),
+				TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+			},
+			expectError:         true,
+			expectErrorContains: "active_until (2025-12-31) must not be before active_from (2026-01-01)",
+		},
+		{
+			name: "invalid - years outside window",
+			issue: Issue{
+				Name:           "test",
+				CreationMonths: []Month{April},
+				Years:          []int{2024},
+				ActiveFrom:     stringPtr("2025-01-01"),
+				TemplateFile:   stringPtr(".github/ISSUE_TEMPLATE/test.md"),
+			},
+			expectError:         true,
+			expectErrorContains: "none of the listed years overlaps",
 		},
 		{
 			name: "invalid - nil template_file",